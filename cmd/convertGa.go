@@ -15,12 +15,34 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"github.com/chr-fritz/knx-exporter/pkg/knx"
 )
 
-type ConvertGaOptions struct{}
+type ConvertGaOptions struct {
+	projectPassword   string
+	merge             bool
+	vanished          string
+	rules             string
+	rangeLabels       []string
+	rangePrefix       []int
+	collisions        string
+	filter            string
+	include           filterFlags
+	exclude           filterFlags
+	excludeCentral    bool
+	excludeUnfiltered bool
+}
+
+// filterFlags contains the values of either the include or exclude filter flags.
+type filterFlags struct {
+	ranges []string
+	names  []string
+	dpts   []string
+}
 
 func NewConvertGaOptions() *ConvertGaOptions {
 	return &ConvertGaOptions{}
@@ -29,30 +51,167 @@ func NewConvertGaOptions() *ConvertGaOptions {
 func NewConvertGaCommand() *cobra.Command {
 	convertGaOptions := NewConvertGaOptions()
 
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "convertGA [sourceFile] [targetFile]",
-		Short: "Converts the ETS 5 XML group address export into the configuration format.",
-		Long: `Converts the ETS 5 XML group address export into the configuration format.
+		Short: "Converts an ETS group address export or an ETS project into the configuration format.",
+		Long: `Converts an ETS group address export or an ETS project into the configuration format.
+
+It takes either the XML or CSV group address export from the ETS 5 or ETS 6 tool or the ETS project
+file (.knxproj) and converts it into the yaml format used by the exporter. The format of the source
+file is detected automatically. Password protected project files can be opened using the --password
+flag.
+
+Using --merge the group addresses will be merged into an existing configuration. It updates the
+names, comments and data point types of already configured group addresses and keeps all other
+settings. New group addresses will be added and group addresses which are not part of the export
+anymore will be either flagged as vanished or removed depending on --vanished.
+
+Using --rules a rules file can be given which derives the export flag, metric type, active reading
+and labels from the data point types, names and group ranges of the group addresses. It also adds
+the names of the group ranges as labels (by default floor and room). While merging the rules only
+apply to new group addresses.
+
+The names of the group ranges can also be used as metric name prefix using --range-prefix. i.e.
+--range-prefix 1 converts "Temperature" within the middle group "Kitchen" into "Kitchen_Temperature".
+Group addresses with the same metric name and labels or with different metric types for the same
+name are reported as collisions. Using --collisions they can be resolved: suffix-ga appends the
+group address to the names, range-labels adds the group range names as labels and fail aborts the
+conversion.
 
-It takes the XML group address export from the ETS 5 tool and converts it into the yaml format
-used by the exporter.`,
+The group addresses to convert can be selected by --include-* and --exclude-* flags or a filter file
+given by --filter. Every flag value is an own condition. A group address is converted if it matches
+any include condition (or if there are none) and no exclude condition. Ranges can be either a main
+group like "1", a middle group like "1/2" or a range of group addresses like "1/2/0-1/2/50".`,
 		Args:              cobra.ExactArgs(2),
 		RunE:              convertGaOptions.run,
 		ValidArgsFunction: convertGaOptions.ValidArgs,
 	}
+
+	cmd.Flags().StringVar(&convertGaOptions.projectPassword, "password", "", "The password of a password protected ETS project file.")
+	cmd.Flags().BoolVarP(&convertGaOptions.merge, "merge", "m", false, "Merge the group addresses into the existing target configuration.")
+	cmd.Flags().StringVar(&convertGaOptions.vanished, "vanished", string(knx.FlagVanished), "How to handle group addresses which are not part of the export anymore while merging. Can be flag or remove.")
+	cmd.Flags().StringVar(&convertGaOptions.rules, "rules", "", "A rules file to derive export, metric type, active reading and labels from the group addresses.")
+	_ = cmd.MarkFlagFilename("rules", "yaml", "yml")
+	cmd.Flags().StringSliceVar(&convertGaOptions.rangeLabels, "range-labels", nil, "The label names for the group range levels starting with the main group. Empty names skip the level. Defaults to floor,room if rules are used.")
+	cmd.Flags().IntSliceVar(&convertGaOptions.rangePrefix, "range-prefix", nil, "The group range levels whose names are prepended to the metric names. i.e. 0,1 for main and middle group.")
+	cmd.Flags().StringVar(&convertGaOptions.collisions, "collisions", "", "How to resolve metric name collisions. Can be suffix-ga, range-labels or fail. By default, they are only reported.")
+	cmd.Flags().StringVar(&convertGaOptions.filter, "filter", "", "A filter file to select the group addresses to convert.")
+	_ = cmd.MarkFlagFilename("filter", "yaml", "yml")
+	cmd.Flags().StringSliceVar(&convertGaOptions.include.ranges, "include-range", nil, "Only convert group addresses within the given main groups, middle groups or ranges.")
+	cmd.Flags().StringSliceVar(&convertGaOptions.exclude.ranges, "exclude-range", nil, "Don't convert group addresses within the given main groups, middle groups or ranges.")
+	cmd.Flags().StringSliceVar(&convertGaOptions.include.names, "include-name", nil, "Only convert group addresses whose names matches the given regular expressions.")
+	cmd.Flags().StringSliceVar(&convertGaOptions.exclude.names, "exclude-name", nil, "Don't convert group addresses whose names matches the given regular expressions.")
+	cmd.Flags().StringSliceVar(&convertGaOptions.include.dpts, "include-dpt", nil, "Only convert group addresses with the given data point types or families like 9.*.")
+	cmd.Flags().StringSliceVar(&convertGaOptions.exclude.dpts, "exclude-dpt", nil, "Don't convert group addresses with the given data point types or families like 9.*.")
+	cmd.Flags().BoolVar(&convertGaOptions.excludeCentral, "exclude-central", false, "Don't convert group addresses which are marked as central.")
+	cmd.Flags().BoolVar(&convertGaOptions.excludeUnfiltered, "exclude-unfiltered", false, "Don't convert group addresses which are marked as unfiltered.")
+	_ = cmd.RegisterFlagCompletionFunc("collisions", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(knx.SuffixAddress), string(knx.AddRangeLabels), string(knx.FailOnCollision)}, cobra.ShellCompDirectiveDefault
+	})
+	_ = cmd.RegisterFlagCompletionFunc("vanished", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(knx.FlagVanished), string(knx.RemoveVanished)}, cobra.ShellCompDirectiveDefault
+	})
+	_ = cmd.RegisterFlagCompletionFunc("password", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
 }
 
 // ValidArgs returns a list of possible arguments.
 func (i *ConvertGaOptions) ValidArgs(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
 	if len(args) == 0 {
-		return []string{"xml"}, cobra.ShellCompDirectiveFilterFileExt
+		return []string{"xml", "csv", "knxproj"}, cobra.ShellCompDirectiveFilterFileExt
 	} else {
 		return nil, cobra.ShellCompDirectiveFilterDirs
 	}
 }
 
-func (i *ConvertGaOptions) run(_ *cobra.Command, args []string) error {
-	return knx.ConvertGroupAddresses(args[0], args[1])
+func (i *ConvertGaOptions) run(cmd *cobra.Command, args []string) error {
+	vanished := knx.VanishedPolicy(i.vanished)
+	if vanished != knx.FlagVanished && vanished != knx.RemoveVanished {
+		return fmt.Errorf("invalid value \"%s\" for --vanished. must be either flag or remove", i.vanished)
+	}
+	collisions, err := knx.ParseCollisionStrategy(i.collisions)
+	if err != nil {
+		return err
+	}
+
+	var rules *knx.ConvertRules
+	if i.rules != "" {
+		if rules, err = knx.ReadConvertRules(i.rules); err != nil {
+			return err
+		}
+	}
+
+	filter, err := i.convertFilter()
+	if err != nil {
+		return err
+	}
+
+	var rangeLabels []string
+	if cmd.Flags().Changed("range-labels") {
+		rangeLabels = append([]string{}, i.rangeLabels...)
+	}
+	var rangePrefix []int
+	if cmd.Flags().Changed("range-prefix") {
+		rangePrefix = append([]int{}, i.rangePrefix...)
+	}
+
+	summary, err := knx.ConvertGroupAddresses(args[0], args[1], knx.ConvertOptions{
+		ProjectPassword: i.projectPassword,
+		Merge:           i.merge,
+		Vanished:        vanished,
+		Rules:           rules,
+		RangeLabels:     rangeLabels,
+		RangePrefix:     rangePrefix,
+		Collisions:      collisions,
+		Filter:          filter,
+	})
+	if err != nil {
+		return err
+	}
+	cmd.Print(summary.String())
+	return nil
+}
+
+// convertFilter creates the filter from the filter file and the include and exclude flags. It returns nil if neither
+// is given.
+func (i *ConvertGaOptions) convertFilter() (*knx.ConvertFilter, error) {
+	filter := &knx.ConvertFilter{}
+	if i.filter != "" {
+		var err error
+		if filter, err = knx.ReadConvertFilter(i.filter); err != nil {
+			return nil, err
+		}
+	}
+	filter.Include = append(filter.Include, i.include.conditions()...)
+	filter.Exclude = append(filter.Exclude, i.exclude.conditions()...)
+	enabled := true
+	if i.excludeCentral {
+		filter.Exclude = append(filter.Exclude, knx.FilterCondition{Central: &enabled})
+	}
+	if i.excludeUnfiltered {
+		filter.Exclude = append(filter.Exclude, knx.FilterCondition{Unfiltered: &enabled})
+	}
+
+	if len(filter.Include) == 0 && len(filter.Exclude) == 0 {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+func (f filterFlags) conditions() []knx.FilterCondition {
+	var conditions []knx.FilterCondition
+	for _, r := range f.ranges {
+		conditions = append(conditions, knx.FilterCondition{Range: r})
+	}
+	for _, name := range f.names {
+		conditions = append(conditions, knx.FilterCondition{Name: name})
+	}
+	for _, dpt := range f.dpts {
+		conditions = append(conditions, knx.FilterCondition{DPT: dpt})
+	}
+	return conditions
 }
 
 func init() {
@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
 	"testing"
 
@@ -23,12 +24,21 @@ import (
 
 func TestRunConvertGaCommand(t *testing.T) {
 	tests := []struct {
-		name    string
-		src     string
-		wantErr bool
+		name     string
+		src      string
+		password string
+		rules    string
+		filter   string
+		wantErr  bool
 	}{
-		{"full", "../pkg/knx/fixtures/ga-export.xml", false},
-		{"source do not exists", "fixtures/invalid.xml", true},
+		{"full", "../pkg/knx/fixtures/ga-export.xml", "", "", "", false},
+		{"protected project", "../pkg/knx/export/fixtures/project-ets6-protected.knxproj", "secret", "", "", false},
+		{"protected project without password", "../pkg/knx/export/fixtures/project-ets6-protected.knxproj", "", "", "", true},
+		{"with rules", "../pkg/knx/fixtures/ga-export.xml", "", "../scripts/convertRules.yaml", "", false},
+		{"rules do not exists", "../pkg/knx/fixtures/ga-export.xml", "", "fixtures/invalid.yaml", "", true},
+		{"with filter", "../pkg/knx/fixtures/ga-export.xml", "", "", "../pkg/knx/fixtures/convert-filter.yaml", false},
+		{"filter do not exists", "../pkg/knx/fixtures/ga-export.xml", "", "", "fixtures/invalid.yaml", true},
+		{"source do not exists", "fixtures/invalid.xml", "", "", "", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -38,8 +48,14 @@ func TestRunConvertGaCommand(t *testing.T) {
 				_ = os.Remove(tmpFile.Name())
 			}()
 			cmd := NewConvertGaCommand()
+			assert.NoError(t, cmd.Flags().Set("password", tt.password))
+			assert.NoError(t, cmd.Flags().Set("rules", tt.rules))
+			assert.NoError(t, cmd.Flags().Set("filter", tt.filter))
 
-			if err := cmd.RunE(nil, []string{tt.src, tmpFile.Name()}); (err != nil) != tt.wantErr {
+			output := &bytes.Buffer{}
+			cmd.SetOut(output)
+
+			if err := cmd.RunE(cmd, []string{tt.src, tmpFile.Name()}); (err != nil) != tt.wantErr {
 				t.Errorf("ConvertGroupAddresses() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -48,6 +64,7 @@ func TestRunConvertGaCommand(t *testing.T) {
 			}
 
 			assert.FileExists(t, tmpFile.Name())
+			assert.Contains(t, output.String(), "added")
 		})
 	}
 }
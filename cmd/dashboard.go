@@ -0,0 +1,93 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chr-fritz/knx-exporter/pkg/grafana"
+	"github.com/chr-fritz/knx-exporter/pkg/knx"
+)
+
+type DashboardOptions struct {
+	configFile string
+	output     string
+	title      string
+	groupBy    string
+}
+
+func NewDashboardOptions() *DashboardOptions {
+	return &DashboardOptions{}
+}
+
+func NewDashboardCommand() *cobra.Command {
+	dashboardOptions := NewDashboardOptions()
+
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Generates a Grafana dashboard for all exported metrics.",
+		Long: `Generates a Grafana dashboard for all exported metrics of the configuration.
+
+It creates one panel for every exported metric. The panels are grouped into rows by the values of
+the label given by --group-by. The units are taken from the data point types. Boolean values are
+shown as state timeline, counters as increase per interval. The dashboard contains template
+variables for the physical address and all static labels.`,
+		Args: cobra.NoArgs,
+		RunE: dashboardOptions.run,
+	}
+
+	cmd.Flags().StringVarP(&dashboardOptions.configFile, "configFile", "f", "config.yaml", "The knx configuration file.")
+	cmd.Flags().StringVarP(&dashboardOptions.output, "output", "o", "", "The file to write the dashboard into. Default is stdout.")
+	cmd.Flags().StringVar(&dashboardOptions.title, "title", "KNX", "The title of the dashboard.")
+	cmd.Flags().StringVar(&dashboardOptions.groupBy, "group-by", "room", "The label to group the panels by. Empty disables grouping.")
+
+	_ = cmd.RegisterFlagCompletionFunc("configFile", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+	})
+	_ = cmd.RegisterFlagCompletionFunc("output", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json"}, cobra.ShellCompDirectiveFilterFileExt
+	})
+	return cmd
+}
+
+func (i *DashboardOptions) run(cmd *cobra.Command, _ []string) error {
+	config, err := knx.ReadConfig(i.configFile)
+	if err != nil {
+		return err
+	}
+
+	dashboard := grafana.NewDashboard(config, grafana.Options{Title: i.title, GroupBy: i.groupBy})
+	data, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return fmt.Errorf("can not marshal dashboard: %s", err)
+	}
+
+	if i.output == "" {
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return err
+	}
+	if err = os.WriteFile(i.output, data, 0644); err != nil {
+		return fmt.Errorf("can not write dashboard into %s: %s", i.output, err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(NewDashboardCommand())
+}
@@ -0,0 +1,108 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDashboardCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		toFile     bool
+		wantErr    bool
+	}{
+		{"stdout", "../pkg/grafana/fixtures/config.yaml", false, false},
+		{"file", "../pkg/grafana/fixtures/config.yaml", true, false},
+		{"config do not exists", "fixtures/invalid.yaml", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewDashboardCommand()
+			assert.NoError(t, cmd.Flags().Set("configFile", tt.configFile))
+			output := &bytes.Buffer{}
+			cmd.SetOut(output)
+
+			var target string
+			if tt.toFile {
+				tmpFile, err := os.CreateTemp("", "")
+				assert.NoError(t, err)
+				defer func() {
+					_ = os.Remove(tmpFile.Name())
+				}()
+				target = tmpFile.Name()
+				assert.NoError(t, cmd.Flags().Set("output", target))
+			}
+
+			if err := cmd.RunE(cmd, []string{}); (err != nil) != tt.wantErr {
+				t.Errorf("RunE() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			content := output.Bytes()
+			if tt.toFile {
+				var err error
+				content, err = os.ReadFile(target)
+				assert.NoError(t, err)
+			}
+			dashboard := map[string]interface{}{}
+			assert.NoError(t, json.Unmarshal(content, &dashboard))
+			assert.Equal(t, "KNX", dashboard["title"])
+		})
+	}
+}
+
+func TestRunDashboardCommand_stdout(t *testing.T) {
+	cmd := NewDashboardCommand()
+	assert.NoError(t, cmd.Flags().Set("configFile", "../pkg/grafana/fixtures/config.yaml"))
+	stderr := &bytes.Buffer{}
+	cmd.SetErr(stderr)
+
+	content := captureStdout(t, func() {
+		assert.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+	dashboard := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(content, &dashboard))
+	assert.Equal(t, "KNX", dashboard["title"])
+	assert.Empty(t, stderr.String())
+}
+
+// captureStdout returns everything which is written to os.Stdout while running f.
+func captureStdout(t *testing.T, f func()) []byte {
+	stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+	assert.NoError(t, err)
+	defer func() {
+		_ = stdout.Close()
+	}()
+	original := os.Stdout
+	os.Stdout = stdout
+	defer func() {
+		os.Stdout = original
+	}()
+
+	f()
+	content, err := os.ReadFile(stdout.Name())
+	assert.NoError(t, err)
+	return content
+}
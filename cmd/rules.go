@@ -0,0 +1,90 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/chr-fritz/knx-exporter/pkg/alerting"
+	"github.com/chr-fritz/knx-exporter/pkg/knx"
+)
+
+type RulesOptions struct {
+	configFile string
+	output     string
+}
+
+func NewRulesOptions() *RulesOptions {
+	return &RulesOptions{}
+}
+
+func NewRulesCommand() *cobra.Command {
+	rulesOptions := NewRulesOptions()
+
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Generates a Prometheus rule file for the configuration.",
+		Long: `Generates a Prometheus rule file with alerting and recording rules for the configuration.
+
+It creates a staleness alert for every actively read group address which was not updated within
+twice its MaxAge. The threshold alerts are created from the Alerts of the group addresses: Alarm
+fires if the value is 1, Min and Max if the value is lower or greater and OutOfRange if the value
+is outside the valid range of the data point type. They are skipped for group addresses which are
+not exported or export the same series as others. Additionally, it creates a recording rule with
+the hourly increase for every exported counter.`,
+		Args: cobra.NoArgs,
+		RunE: rulesOptions.run,
+	}
+
+	cmd.Flags().StringVarP(&rulesOptions.configFile, "configFile", "f", "config.yaml", "The knx configuration file.")
+	cmd.Flags().StringVarP(&rulesOptions.output, "output", "o", "", "The file to write the rules into. Default is stdout.")
+
+	_ = cmd.RegisterFlagCompletionFunc("configFile", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+	})
+	_ = cmd.RegisterFlagCompletionFunc("output", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+	})
+	return cmd
+}
+
+func (i *RulesOptions) run(cmd *cobra.Command, _ []string) error {
+	config, err := knx.ReadConfig(i.configFile)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(alerting.NewRuleFile(config))
+	if err != nil {
+		return fmt.Errorf("can not marshal rules: %s", err)
+	}
+
+	if i.output == "" {
+		_, err = fmt.Fprint(cmd.OutOrStdout(), string(data))
+		return err
+	}
+	if err = os.WriteFile(i.output, data, 0644); err != nil {
+		return fmt.Errorf("can not write rules into %s: %s", i.output, err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(NewRulesCommand())
+}
@@ -0,0 +1,92 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chr-fritz/knx-exporter/pkg/alerting"
+)
+
+func TestRunRulesCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		toFile     bool
+		wantErr    bool
+	}{
+		{"stdout", "../pkg/alerting/fixtures/config.yaml", false, false},
+		{"file", "../pkg/alerting/fixtures/config.yaml", true, false},
+		{"config do not exists", "fixtures/invalid.yaml", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewRulesCommand()
+			assert.NoError(t, cmd.Flags().Set("configFile", tt.configFile))
+			output := &bytes.Buffer{}
+			cmd.SetOut(output)
+
+			var target string
+			if tt.toFile {
+				tmpFile, err := os.CreateTemp("", "")
+				assert.NoError(t, err)
+				defer func() {
+					_ = os.Remove(tmpFile.Name())
+				}()
+				target = tmpFile.Name()
+				assert.NoError(t, cmd.Flags().Set("output", target))
+			}
+
+			if err := cmd.RunE(cmd, []string{}); (err != nil) != tt.wantErr {
+				t.Errorf("RunE() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			content := output.Bytes()
+			if tt.toFile {
+				var err error
+				content, err = os.ReadFile(target)
+				assert.NoError(t, err)
+			}
+			rules := alerting.RuleFile{}
+			assert.NoError(t, yaml.Unmarshal(content, &rules))
+			assert.Len(t, rules.Groups, 1)
+			assert.NotEmpty(t, rules.Groups[0].Rules)
+		})
+	}
+}
+
+func TestRunRulesCommand_stdout(t *testing.T) {
+	cmd := NewRulesCommand()
+	assert.NoError(t, cmd.Flags().Set("configFile", "../pkg/alerting/fixtures/config.yaml"))
+	stderr := &bytes.Buffer{}
+	cmd.SetErr(stderr)
+
+	content := captureStdout(t, func() {
+		assert.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+	rules := alerting.RuleFile{}
+	assert.NoError(t, yaml.Unmarshal(content, &rules))
+	assert.Len(t, rules.Groups, 1)
+	assert.Empty(t, stderr.String())
+}
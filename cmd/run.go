@@ -16,9 +16,11 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/coreos/go-systemd/v22/daemon"
@@ -31,9 +33,24 @@ import (
 )
 
 const RunPortParm = "exporter.port"
+const RunListenAddressParm = "exporter.listenAddress"
+const RunWebConfigFileParm = "exporter.webConfigFile"
+const RunWriteApiParm = "exporter.writeApi"
+const RunAlertmanagerWebhookParm = "exporter.alertmanagerWebhook"
 const RunConfigFileParm = "exporter.configFile"
 const RunRestartParm = "exporter.restart"
 const WithGoMetricsParamName = "exporter.goMetrics"
+const ReadyConnectedParm = "exporter.readiness.connected"
+const ReadyStartupReadTimeoutParm = "exporter.readiness.startupReadTimeout"
+const ReadyFreshPercentParm = "exporter.readiness.freshPercent"
+
+const RemoteWriteUrlParm = "exporter.remoteWrite.url"
+const RemoteWriteIntervalParm = "exporter.remoteWrite.interval"
+const RemoteWriteBufferDirParm = "exporter.remoteWrite.bufferDir"
+const RemoteWriteLabelsParm = "exporter.remoteWrite.labels"
+const RemoteWriteUsernameParm = "exporter.remoteWrite.username"
+const RemoteWritePasswordParm = "exporter.remoteWrite.password"
+const RemoteWriteBearerTokenParm = "exporter.remoteWrite.bearerToken"
 
 type RunOptions struct {
 	aliveCheckInterval time.Duration
@@ -57,21 +74,55 @@ func NewRunCommand() *cobra.Command {
 	}
 
 	cmd.Flags().Uint16P("port", "p", 8080, "The port where all metrics should be exported.")
+	cmd.Flags().String("listenAddress", "", "The address where all metrics should be exported. i.e. [::]:8080 or unix:///run/knx-exporter.sock. Overrides the port if set.")
+	cmd.Flags().String("webConfigFile", "", "The web configuration file of the Prometheus exporter-toolkit which enables TLS and authentication.")
+	cmd.Flags().Bool("writeApi", false, "Enable the api to send values to writable group addresses. Requires authentication using the web configuration file.")
+	cmd.Flags().Bool("alertmanagerWebhook", false, "Enable the Alertmanager webhook which writes group addresses. It is also enabled if any installation has Alertmanager actions. Requires authentication using the web configuration file.")
 	cmd.Flags().StringP("configFile", "f", "config.yaml", "The knx configuration file.")
-	cmd.Flags().StringP("restart", "r", "health", "The restart behaviour. Can be health or exit")
+	cmd.Flags().StringP("restart", "r", "health", "The restart behaviour if the exporter can not reconnect to the KNX gateway or another component fails. Can be health or exit")
 	cmd.Flags().BoolP("withGoMetrics", "g", true, "Should the go metrics also be exported?")
+	cmd.Flags().Bool("readyConnected", true, "Report not ready while the KNX gateway is not connected.")
+	cmd.Flags().Duration("readyStartupReadTimeout", 2*time.Minute, "Report not ready until the reads after startup are finished or the timeout is exceeded. Disabled if 0.")
+	cmd.Flags().Float64("readyFreshPercent", 0, "Report not ready if less than this percentage of the actively read group addresses have a value younger than their MaxAge. Disabled if 0.")
+	cmd.Flags().String("remoteWriteUrl", "", "The remote_write endpoint to which all metrics are pushed additionally. Disabled if empty.")
+	cmd.Flags().Duration("remoteWriteInterval", 30*time.Second, "The interval between two pushes to the remote_write endpoint.")
+	cmd.Flags().String("remoteWriteBufferDir", "", "The directory to buffer pushes while the remote_write endpoint is not reachable. Buffered in memory if empty.")
+	cmd.Flags().StringToString("remoteWriteLabels", nil, "Additional labels for all pushed series. i.e. site=home")
+	cmd.Flags().String("remoteWriteUsername", "", "The username for basic authentication at the remote_write endpoint.")
+	cmd.Flags().String("remoteWritePassword", "", "The password for basic authentication at the remote_write endpoint.")
+	cmd.Flags().String("remoteWriteBearerToken", "", "The bearer token for authentication at the remote_write endpoint.")
 
 	_ = viper.BindPFlag(RunPortParm, cmd.Flags().Lookup("port"))
+	_ = viper.BindPFlag(RunListenAddressParm, cmd.Flags().Lookup("listenAddress"))
+	_ = viper.BindPFlag(RunWebConfigFileParm, cmd.Flags().Lookup("webConfigFile"))
+	_ = viper.BindPFlag(RunWriteApiParm, cmd.Flags().Lookup("writeApi"))
+	_ = viper.BindPFlag(RunAlertmanagerWebhookParm, cmd.Flags().Lookup("alertmanagerWebhook"))
 	_ = viper.BindPFlag(RunConfigFileParm, cmd.Flags().Lookup("configFile"))
 	_ = viper.BindPFlag(RunRestartParm, cmd.Flags().Lookup("restart"))
 	_ = viper.BindPFlag(WithGoMetricsParamName, cmd.Flags().Lookup("withGoMetrics"))
+	_ = viper.BindPFlag(ReadyConnectedParm, cmd.Flags().Lookup("readyConnected"))
+	_ = viper.BindPFlag(ReadyStartupReadTimeoutParm, cmd.Flags().Lookup("readyStartupReadTimeout"))
+	_ = viper.BindPFlag(ReadyFreshPercentParm, cmd.Flags().Lookup("readyFreshPercent"))
+	_ = viper.BindPFlag(RemoteWriteUrlParm, cmd.Flags().Lookup("remoteWriteUrl"))
+	_ = viper.BindPFlag(RemoteWriteIntervalParm, cmd.Flags().Lookup("remoteWriteInterval"))
+	_ = viper.BindPFlag(RemoteWriteBufferDirParm, cmd.Flags().Lookup("remoteWriteBufferDir"))
+	_ = viper.BindPFlag(RemoteWriteLabelsParm, cmd.Flags().Lookup("remoteWriteLabels"))
+	_ = viper.BindPFlag(RemoteWriteUsernameParm, cmd.Flags().Lookup("remoteWriteUsername"))
+	_ = viper.BindPFlag(RemoteWritePasswordParm, cmd.Flags().Lookup("remoteWritePassword"))
+	_ = viper.BindPFlag(RemoteWriteBearerTokenParm, cmd.Flags().Lookup("remoteWriteBearerToken"))
 
 	_ = cmd.RegisterFlagCompletionFunc("configFile", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
 	})
+	_ = cmd.RegisterFlagCompletionFunc("webConfigFile", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+	})
 	_ = cmd.RegisterFlagCompletionFunc("port", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	})
+	_ = cmd.RegisterFlagCompletionFunc("remoteWriteBufferDir", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	})
 	_ = cmd.RegisterFlagCompletionFunc("restart", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"health", "exit"}, cobra.ShellCompDirectiveDefault
 	})
@@ -84,51 +135,193 @@ func (i *RunOptions) run(_ *cobra.Command, _ []string) {
 
 	exporter := metrics.NewExporter(uint16(viper.GetUint(RunPortParm)), viper.GetBool(WithGoMetricsParamName))
 
+	webConfig := metrics.WebConfig{
+		ListenAddress: viper.GetString(RunListenAddressParm),
+		ConfigFile:    viper.GetString(RunWebConfigFileParm),
+	}
+	err := exporter.ConfigureWeb(webConfig)
+	if err != nil {
+		slog.Error("Unable to configure web server: " + err.Error())
+		return
+	}
+	writeApi := viper.GetBool(RunWriteApiParm)
+	if writeApi {
+		if err = requireAuthentication(webConfig); err != nil {
+			slog.Error("Unable to enable write api: " + err.Error())
+			return
+		}
+	}
+
 	exporter.AddLivenessCheck("goroutine-threshold", healthcheck.GoroutineCountCheck(100))
-	metricsExporter, err := i.initAndRunMetricsExporter(ctx, exporter)
+	if url := viper.GetString(RemoteWriteUrlParm); url != "" {
+		err = exporter.EnableRemoteWrite(metrics.RemoteWriteConfig{
+			URL:         url,
+			Interval:    viper.GetDuration(RemoteWriteIntervalParm),
+			BufferDir:   viper.GetString(RemoteWriteBufferDirParm),
+			Labels:      viper.GetStringMapString(RemoteWriteLabelsParm),
+			Username:    viper.GetString(RemoteWriteUsernameParm),
+			Password:    viper.GetString(RemoteWritePasswordParm),
+			BearerToken: viper.GetString(RemoteWriteBearerTokenParm),
+		})
+		if err != nil {
+			slog.Error("Unable to enable remote write: " + err.Error())
+			return
+		}
+	}
+	metricsExporters, err := i.initAndRunMetricsExporters(ctx, exporter)
 	if err != nil {
 		slog.Error("Unable to init metrics exporter: " + err.Error())
 		return
 	}
 
-	go i.aliveCheck(ctx, stop, metricsExporter)
+	if err = registerHandlers(exporter, metricsExporters, webConfig, writeApi); err != nil {
+		slog.Error("Unable to register handlers: " + err.Error())
+		return
+	}
+	for _, metricsExporter := range metricsExporters {
+		go i.aliveCheck(ctx, stop, metricsExporter)
+	}
 
 	if err = exporter.Run(ctx); err != nil {
 		slog.Error("Can not run metrics exporter: " + err.Error())
 	}
 }
 
+// requireAuthentication returns an error if the web config file does not enable TLS together with basic
+// authentication or client certificates.
+func requireAuthentication(webConfig metrics.WebConfig) error {
+	authenticated, err := webConfig.RequiresAuthentication()
+	if err != nil {
+		return err
+	}
+	if !authenticated {
+		return fmt.Errorf("the web config file must enable tls together with basic authentication or client certificates")
+	}
+	return nil
+}
+
+// registerHandlers registers the api, the status page and the enabled endpoints which write group addresses.
+func registerHandlers(exporter metrics.Exporter, metricsExporters []knx.MetricsExporter, webConfig metrics.WebConfig, writeApi bool) error {
+	exporter.Handle("/api/", knx.NewAPIHandler(metricsExporters))
+	exporter.Handle("/status", knx.NewStatusPageHandler(metricsExporters))
+	if alertmanagerWebhookEnabled(metricsExporters) {
+		if err := requireAuthentication(webConfig); err != nil {
+			return fmt.Errorf("can not enable alertmanager webhook: %s", err)
+		}
+		exporter.Handle("POST /api/v1/alerts", knx.NewAlertmanagerHandler(metricsExporters))
+	}
+	if writeApi {
+		exporter.Handle("POST /api/v1/write", knx.NewWriteHandler(metricsExporters))
+	}
+	return nil
+}
+
+// alertmanagerWebhookEnabled returns true if the webhook is enabled by flag or any installation has alert actions.
+func alertmanagerWebhookEnabled(metricsExporters []knx.MetricsExporter) bool {
+	if viper.GetBool(RunAlertmanagerWebhookParm) {
+		return true
+	}
+	for _, metricsExporter := range metricsExporters {
+		if config := metricsExporter.Config().Alertmanager; config != nil && len(config.Actions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (i *RunOptions) aliveCheck(ctx context.Context, cancelFunc context.CancelFunc, metricsExporter knx.MetricsExporter) {
 	ticker := time.NewTicker(i.aliveCheckInterval)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
-			aliveErr := metricsExporter.IsAlive()
-			if aliveErr != nil {
-				_, _ = daemon.SdNotify(false, "STATUS=Metrics Exporter is not alive anymore: "+aliveErr.Error())
-				_, _ = daemon.SdNotify(false, "ERROR=1")
-				if viper.GetString(RunRestartParm) == "exit" {
-					cancelFunc()
-				}
-			}
+			i.checkHealth(ctx, cancelFunc, metricsExporter)
 		case <-ctx.Done():
 			cancelFunc()
+			return
 		}
 	}
 }
 
-func (i *RunOptions) initAndRunMetricsExporter(ctx context.Context, exporter metrics.Exporter) (knx.MetricsExporter, error) {
-	metricsExporter, err := knx.NewMetricsExporter(viper.GetString(RunConfigFileParm), exporter)
+// checkHealth reconnects to the KNX gateway if only components bound to the connection are down. Otherwise, or if the
+// reconnect fails, it reports the exporter as not alive and stops it if the restart behaviour is exit.
+func (i *RunOptions) checkHealth(ctx context.Context, cancelFunc context.CancelFunc, metricsExporter knx.MetricsExporter) {
+	var failures []string
+	recoverable := true
+	for _, component := range metricsExporter.Health() {
+		if component.State == knx.ComponentDown {
+			failures = append(failures, component.Component+": "+component.LastError)
+			recoverable = recoverable && component.Recoverable
+		}
+	}
+	if len(failures) == 0 {
+		return
+	}
+
+	aliveErr := strings.Join(failures, ", ")
+	logger := slog.Default()
+	if metricsExporter.Installation() != "" {
+		logger = logger.With("installation", metricsExporter.Installation())
+	}
+	if recoverable {
+		logger.Warn("Reconnect to the KNX gateway as the exporter is not alive anymore: " + aliveErr)
+		err := metricsExporter.Reconnect(ctx)
+		if err == nil {
+			logger.Info("Reconnected to the KNX gateway")
+			_, _ = daemon.SdNotify(false, "STATUS=Reconnected to the KNX gateway")
+			return
+		}
+		aliveErr += ", reconnect: " + err.Error()
+	}
+
+	logger.Error("Metrics Exporter is not alive anymore: " + aliveErr)
+	_, _ = daemon.SdNotify(false, "STATUS=Metrics Exporter is not alive anymore: "+aliveErr)
+	_, _ = daemon.SdNotify(false, "ERROR=1")
+	if viper.GetString(RunRestartParm) == "exit" {
+		cancelFunc()
+	}
+}
+
+func (i *RunOptions) initAndRunMetricsExporters(ctx context.Context, exporter metrics.Exporter) ([]knx.MetricsExporter, error) {
+	metricsExporters, err := knx.NewMetricsExporters(viper.GetString(RunConfigFileParm), exporter)
 	if err != nil {
 		return nil, err
 	}
 
-	exporter.AddLivenessCheck("knxConnection", metricsExporter.IsAlive)
-	if e := metricsExporter.Run(ctx); e != nil {
-		return nil, e
+	for _, metricsExporter := range metricsExporters {
+		checkName := "knxConnection"
+		if metricsExporter.Installation() != "" {
+			checkName += "-" + metricsExporter.Installation()
+		}
+		exporter.AddLivenessCheck(checkName, metricsExporter.IsAlive)
+		addReadinessChecks(exporter, metricsExporter)
+		if e := metricsExporter.Run(ctx); e != nil {
+			return nil, e
+		}
 	}
 
-	return metricsExporter, nil
+	return metricsExporters, nil
+}
+
+// addReadinessChecks registers the enabled readiness checks of the metrics exporter.
+func addReadinessChecks(exporter metrics.Exporter, metricsExporter knx.MetricsExporter) {
+	suffix := ""
+	if metricsExporter.Installation() != "" {
+		suffix = "-" + metricsExporter.Installation()
+	}
+	if viper.GetBool(ReadyConnectedParm) {
+		exporter.AddReadinessCheck("knxConnected"+suffix, metricsExporter.IsConnected)
+	}
+	if timeout := viper.GetDuration(ReadyStartupReadTimeoutParm); timeout > 0 {
+		exporter.AddReadinessCheck("knxStartupReads"+suffix, func() error {
+			return metricsExporter.IsStartupReadFinished(timeout)
+		})
+	}
+	if minPercent := viper.GetFloat64(ReadyFreshPercentParm); minPercent > 0 {
+		exporter.AddReadinessCheck("knxFreshValues"+suffix, func() error {
+			return metricsExporter.HasFreshValues(minPercent)
+		})
+	}
 }
 
 func init() {
@@ -17,19 +17,27 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/heptiolabs/healthcheck"
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 
+	"github.com/chr-fritz/knx-exporter/pkg/knx"
 	knxFake "github.com/chr-fritz/knx-exporter/pkg/knx/fake"
+	"github.com/chr-fritz/knx-exporter/pkg/metrics"
+	metricsFake "github.com/chr-fritz/knx-exporter/pkg/metrics/fake"
 	"github.com/golang/mock/gomock"
 )
 
 func TestRunOptions_aliveCheck(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	ctx, cancelFunc := context.WithTimeout(context.Background(), 15*time.Millisecond)
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
 	defer cancelFunc()
 
 	viper.Set(RunRestartParm, "exit")
@@ -38,13 +46,162 @@ func TestRunOptions_aliveCheck(t *testing.T) {
 	}
 
 	knxExporter := knxFake.NewMockMetricsExporter(ctrl)
-
+	knxExporter.EXPECT().Installation().Return("").AnyTimes()
 	knxExporter.EXPECT().
-		IsAlive().
+		Health().
 		MinTimes(1).
-		Return(nil).
-		Return(fmt.Errorf("no connection"))
+		Return([]knx.ComponentHealth{{Component: knx.ComponentSnapshotHandler, State: knx.ComponentDown, LastError: "closed"}})
+
+	done := make(chan struct{})
+	go func() {
+		i.aliveCheck(ctx, cancelFunc, knxExporter)
+		close(done)
+	}()
+	select {
+	case <-done:
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("alive check was not stopped")
+	}
+}
+
+func TestRunOptions_checkHealth(t *testing.T) {
+	connectionDown := knx.ComponentHealth{Component: knx.ComponentConnection, State: knx.ComponentDown, Recoverable: true, LastError: "not connected"}
+	listenerDown := knx.ComponentHealth{Component: knx.ComponentListener, State: knx.ComponentDown, Recoverable: true, LastError: "listener is closed"}
+	outputDown := knx.ComponentHealth{Component: "mqtt", State: knx.ComponentDown, LastError: "snapshot output is closed"}
+	outputUp := knx.ComponentHealth{Component: "mqtt", State: knx.ComponentUp}
+	tests := []struct {
+		name          string
+		restart       string
+		components    []knx.ComponentHealth
+		wantReconnect bool
+		reconnectErr  error
+		wantCancel    bool
+	}{
+		{"healthy", "exit", []knx.ComponentHealth{outputUp}, false, nil, false},
+		{"reconnected", "exit", []knx.ComponentHealth{connectionDown, listenerDown, outputUp}, true, nil, false},
+		{"reconnect failed", "exit", []knx.ComponentHealth{connectionDown}, true, fmt.Errorf("no route to host"), true},
+		{"reconnect failed without exit", "health", []knx.ComponentHealth{connectionDown}, true, fmt.Errorf("no route to host"), false},
+		{"not recoverable", "exit", []knx.ComponentHealth{listenerDown, outputDown}, false, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			viper.Set(RunRestartParm, tt.restart)
+			knxExporter := knxFake.NewMockMetricsExporter(ctrl)
+			knxExporter.EXPECT().Installation().Return("home").AnyTimes()
+			knxExporter.EXPECT().Health().Return(tt.components)
+			if tt.wantReconnect {
+				knxExporter.EXPECT().Reconnect(gomock.Any()).Return(tt.reconnectErr)
+			}
+
+			canceled := false
+			i := NewRunOptions()
+			i.checkHealth(context.Background(), func() { canceled = true }, knxExporter)
+			assert.Equal(t, tt.wantCancel, canceled)
+		})
+	}
+}
+
+func Test_addReadinessChecks(t *testing.T) {
+	tests := []struct {
+		name               string
+		installation       string
+		connected          bool
+		startupReadTimeout time.Duration
+		freshPercent       float64
+		want               []string
+	}{
+		{"defaults", "", true, 2 * time.Minute, 0, []string{"knxConnected", "knxStartupReads"}},
+		{"disabled", "", false, 0, 0, nil},
+		{"installation with fresh values", "home", true, 0, 80, []string{"knxConnected-home", "knxFreshValues-home"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
 
-	go i.aliveCheck(ctx, cancelFunc, knxExporter)
-	time.Sleep(20 * time.Millisecond)
+			viper.Set(ReadyConnectedParm, tt.connected)
+			viper.Set(ReadyStartupReadTimeoutParm, tt.startupReadTimeout)
+			viper.Set(ReadyFreshPercentParm, tt.freshPercent)
+
+			knxExporter := knxFake.NewMockMetricsExporter(ctrl)
+			knxExporter.EXPECT().Installation().Return(tt.installation).AnyTimes()
+			knxExporter.EXPECT().IsConnected().Return(nil).AnyTimes()
+			knxExporter.EXPECT().IsStartupReadFinished(tt.startupReadTimeout).Return(fmt.Errorf("not finished")).AnyTimes()
+			knxExporter.EXPECT().HasFreshValues(tt.freshPercent).Return(nil).AnyTimes()
+
+			var got []string
+			exporter := metricsFake.NewMockExporter(ctrl)
+			exporter.EXPECT().AddReadinessCheck(gomock.Any(), gomock.Any()).
+				Do(func(name string, check healthcheck.Check) {
+					got = append(got, name)
+					if name == "knxStartupReads" {
+						assert.Error(t, check())
+					} else {
+						assert.NoError(t, check())
+					}
+				}).
+				AnyTimes()
+
+			addReadinessChecks(exporter, knxExporter)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_registerHandlers(t *testing.T) {
+	webConfigFile := filepath.Join(t.TempDir(), "web.yaml")
+	assert.NoError(t, os.WriteFile(webConfigFile, []byte("tls_server_config:\n  cert_file: server.crt\n  key_file: server.key\nbasic_auth_users:\n  knx: $2y$10$hash\n"), 0600))
+	plainWebConfigFile := filepath.Join(t.TempDir(), "web.yaml")
+	assert.NoError(t, os.WriteFile(plainWebConfigFile, []byte("basic_auth_users:\n  knx: $2y$10$hash\n"), 0600))
+	actions := &knx.AlertmanagerConfig{Actions: []knx.AlertAction{{Matchers: map[string]string{"alertname": "WaterLeakage"}, Address: 1, Firing: "off"}}}
+
+	tests := []struct {
+		name          string
+		webConfigFile string
+		webhookFlag   bool
+		alertmanager  *knx.AlertmanagerConfig
+		writeApi      bool
+		want          []string
+		wantErr       bool
+	}{
+		{"defaults", "", false, nil, false, []string{"/api/", "/status"}, false},
+		{"webhook flag", webConfigFile, true, nil, false, []string{"/api/", "/status", "POST /api/v1/alerts"}, false},
+		{"alert actions", webConfigFile, false, actions, false, []string{"/api/", "/status", "POST /api/v1/alerts"}, false},
+		{"alert actions without authentication", "", false, actions, false, nil, true},
+		{"webhook flag without authentication", "", true, nil, false, nil, true},
+		{"webhook flag with basic authentication without tls", plainWebConfigFile, true, nil, false, nil, true},
+		{"write api", webConfigFile, false, nil, true, []string{"/api/", "/status", "POST /api/v1/write"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			viper.Set(RunAlertmanagerWebhookParm, tt.webhookFlag)
+			defer viper.Set(RunAlertmanagerWebhookParm, false)
+			knxExporter := knxFake.NewMockMetricsExporter(ctrl)
+			knxExporter.EXPECT().Config().Return(&knx.Config{Alertmanager: tt.alertmanager}).AnyTimes()
+
+			var got []string
+			exporter := metricsFake.NewMockExporter(ctrl)
+			exporter.EXPECT().Handle(gomock.Any(), gomock.Any()).
+				Do(func(pattern string, _ http.Handler) {
+					got = append(got, pattern)
+				}).
+				AnyTimes()
+
+			err := registerHandlers(exporter, []knx.MetricsExporter{knxExporter}, metrics.WebConfig{ConfigFile: tt.webConfigFile}, tt.writeApi)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("registerHandlers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
 }
@@ -0,0 +1,263 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerting
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/chr-fritz/knx-exporter/pkg/knx"
+)
+
+const defaultSeverity = "warning"
+
+// RuleFile is a Prometheus rule file.
+type RuleFile struct {
+	Groups []RuleGroup `json:"groups"`
+}
+
+// RuleGroup is a group of rules which are evaluated together.
+type RuleGroup struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is either an alerting or a recording rule.
+type Rule struct {
+	Record      string            `json:"record,omitempty"`
+	Alert       string            `json:"alert,omitempty"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// valueRange is the range of valid values of a data point type.
+type valueRange struct {
+	min float64
+	max float64
+}
+
+// dptRanges contains the valid value ranges of data point types which can be exceeded by telegrams. i.e. sensors
+// which send the invalid value 0x7FFF (670760.96) for DPT 9.xxx. The specific types are preferred over the families.
+var dptRanges = map[string]valueRange{
+	"5.001": {0, 100},
+	"5.003": {0, 360},
+	"9.*":   {-671088.64, 670760},
+	"9.001": {-273, 670760},
+	"9.004": {0, 670760},
+	"9.007": {0, 670760},
+}
+
+// NewRuleFile generates staleness alerts for all exported and actively read group addresses, the threshold alerts defined by the
+// Alerts of the exported group addresses and recording rules for all exported counters. Threshold alerts of group
+// addresses which export the same series as others are skipped.
+func NewRuleFile(config *knx.Config) RuleFile {
+	var ruleFile RuleFile
+	for _, installation := range config.GetInstallations() {
+		name := "knx"
+		if installation.Installation != "" {
+			name += "-" + installation.Installation
+		}
+		ruleFile.Groups = append(ruleFile.Groups, RuleGroup{Name: name, Rules: newRules(installation)})
+	}
+	return ruleFile
+}
+
+func newRules(config *knx.Config) []Rule {
+	var addresses []knx.GroupAddress
+	for address := range config.AddressConfigs {
+		addresses = append(addresses, address)
+	}
+	slices.Sort(addresses)
+
+	// The threshold alerts select the series by the metric name and the labels. So they are ambiguous if multiple
+	// group addresses export the same series.
+	series := make(map[string][]knx.GroupAddress)
+	for _, address := range addresses {
+		if cfg := config.AddressConfigs[address]; cfg.Export {
+			key := metricSelector(config.NameFor(cfg), cfg.Labels)
+			series[key] = append(series[key], address)
+		}
+	}
+
+	rules := make([]Rule, 0)
+	var recorded []string
+	for _, address := range addresses {
+		cfg := config.AddressConfigs[address]
+		// Values with read windows are expected to get stale outside of them. The timestamps of group addresses
+		// which are not exported are not available at all.
+		if cfg.Export && cfg.ReadActive && cfg.MaxAge > 0 && len(cfg.ReadWindows) == 0 {
+			rules = append(rules, stalenessAlerts(config, address, cfg)...)
+		}
+		if cfg.Alerts != nil {
+			if !cfg.Export {
+				slog.Warn("Skip alerts of the group address as it is not exported", "address", address)
+			} else if others := series[metricSelector(config.NameFor(cfg), cfg.Labels)]; len(others) > 1 {
+				slog.Warn("Skip alerts of the group address as other group addresses export the same series. Use Collisions to resolve it.", "address", address, "addresses", others)
+			} else {
+				rules = append(rules, thresholdAlerts(config, address, cfg)...)
+			}
+		}
+
+		name := config.NameFor(cfg)
+		if cfg.Export && strings.ToLower(cfg.MetricType) == "counter" && !slices.Contains(recorded, name) {
+			recorded = append(recorded, name)
+			rules = append(rules, Rule{
+				Record: name + ":increase1h",
+				Expr:   fmt.Sprintf("increase(%s[1h])", name),
+			})
+		}
+	}
+	return rules
+}
+
+// stalenessAlerts returns an alert for a value which was not updated within twice the MaxAge and an alert for a group
+// address which has not reported any value within this time.
+func stalenessAlerts(config *knx.Config, address knx.GroupAddress, cfg *knx.GroupAddressConfig) []Rule {
+	maxAge := 2 * time.Duration(cfg.MaxAge)
+	selector := map[string]string{"groupAddress": address.String()}
+	if config.Installation != "" {
+		selector["installation"] = config.Installation
+	}
+	metric := metricSelector(knx.LastUpdateMetric, selector)
+	return []Rule{
+		{
+			Alert:       "KNXGroupAddressStale",
+			Expr:        fmt.Sprintf("time() - %s > %s", metric, formatFloat(maxAge.Seconds())),
+			Labels:      alertLabels(config, address, cfg, defaultSeverity),
+			Annotations: annotations(cfg, fmt.Sprintf("Group address %s (%s) was not updated within %s", address, config.NameFor(cfg), model.Duration(maxAge))),
+		},
+		{
+			Alert:       "KNXGroupAddressMissing",
+			Expr:        fmt.Sprintf("absent(%s)", metric),
+			For:         model.Duration(maxAge).String(),
+			Labels:      alertLabels(config, address, cfg, defaultSeverity),
+			Annotations: annotations(cfg, fmt.Sprintf("Group address %s (%s) has not reported any value within %s", address, config.NameFor(cfg), model.Duration(maxAge))),
+		},
+	}
+}
+
+func thresholdAlerts(config *knx.Config, address knx.GroupAddress, cfg *knx.GroupAddressConfig) []Rule {
+	alerts := cfg.Alerts
+	severity := alerts.Severity
+	if severity == "" {
+		severity = defaultSeverity
+	}
+	selector := make(map[string]string)
+	for name, value := range cfg.Labels {
+		selector[name] = value
+	}
+	if config.Installation != "" {
+		selector["installation"] = config.Installation
+	}
+	metric := metricSelector(config.NameFor(cfg), selector)
+
+	var rules []Rule
+	newAlert := func(alert string, expr string, summary string) {
+		rule := Rule{
+			Alert:       alert,
+			Expr:        expr,
+			Labels:      alertLabels(config, address, cfg, severity),
+			Annotations: annotations(cfg, fmt.Sprintf("Group address %s (%s) %s", address, config.NameFor(cfg), summary)),
+		}
+		if alerts.For > 0 {
+			rule.For = model.Duration(alerts.For).String()
+		}
+		rules = append(rules, rule)
+	}
+
+	if alerts.Alarm {
+		newAlert("KNXAlarm", metric+" == 1", "reports an alarm")
+	}
+	if alerts.Min != nil {
+		newAlert("KNXValueTooLow", fmt.Sprintf("%s < %s", metric, formatFloat(*alerts.Min)), fmt.Sprintf("is lower than %s", formatFloat(*alerts.Min)))
+	}
+	if alerts.Max != nil {
+		newAlert("KNXValueTooHigh", fmt.Sprintf("%s > %s", metric, formatFloat(*alerts.Max)), fmt.Sprintf("is greater than %s", formatFloat(*alerts.Max)))
+	}
+	if alerts.OutOfRange {
+		r, ok := rangeFor(cfg.DPT)
+		if !ok {
+			slog.Warn("Skip out of range alert as the range of the data point type is unknown", "address", address, "dpt", cfg.DPT)
+		} else {
+			newAlert(
+				"KNXValueOutOfRange",
+				fmt.Sprintf("%s < %s or %s > %s", metric, formatFloat(r.min), metric, formatFloat(r.max)),
+				fmt.Sprintf("is outside the valid range [%s, %s] of DPT %s", formatFloat(r.min), formatFloat(r.max), cfg.DPT),
+			)
+		}
+	}
+	return rules
+}
+
+func alertLabels(config *knx.Config, address knx.GroupAddress, cfg *knx.GroupAddressConfig, severity string) map[string]string {
+	labels := map[string]string{
+		"severity":     severity,
+		"groupAddress": address.String(),
+		"metric":       config.NameFor(cfg),
+	}
+	if config.Installation != "" {
+		labels["installation"] = config.Installation
+	}
+	return labels
+}
+
+// annotations returns the summary and the comment of the group address as description if it is set.
+func annotations(cfg *knx.GroupAddressConfig, summary string) map[string]string {
+	annotations := map[string]string{"summary": summary}
+	if comment := strings.TrimSpace(cfg.Comment); comment != "" {
+		annotations["description"] = comment
+	}
+	return annotations
+}
+
+// rangeFor returns the valid value range of the data point type or of its family.
+func rangeFor(dpt string) (valueRange, bool) {
+	if r, ok := dptRanges[dpt]; ok {
+		return r, true
+	}
+	family, _, _ := strings.Cut(dpt, ".")
+	r, ok := dptRanges[family+".*"]
+	return r, ok
+}
+
+// metricSelector returns the metric name with label matchers for all labels sorted by their names.
+func metricSelector(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	var names []string
+	for label := range labels {
+		names = append(names, label)
+	}
+	slices.Sort(names)
+
+	var matchers []string
+	for _, label := range names {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", label, labels[label]))
+	}
+	return fmt.Sprintf("%s{%s}", name, strings.Join(matchers, ", "))
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,135 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chr-fritz/knx-exporter/pkg/knx"
+)
+
+func TestNewRuleFile(t *testing.T) {
+	config, err := knx.ReadConfig("fixtures/config.yaml")
+	assert.NoError(t, err)
+
+	ruleFile := NewRuleFile(config)
+	assert.Len(t, ruleFile.Groups, 1)
+	assert.Equal(t, "knx", ruleFile.Groups[0].Name)
+
+	type ruleSummary struct {
+		Name     string
+		Expr     string
+		For      string
+		Severity string
+	}
+	var rules []ruleSummary
+	for _, r := range ruleFile.Groups[0].Rules {
+		rules = append(rules, ruleSummary{Name: r.Alert + r.Record, Expr: r.Expr, For: r.For, Severity: r.Labels["severity"]})
+	}
+	assert.Equal(t, []ruleSummary{
+		{"KNXGroupAddressStale", `time() - knx_last_update_timestamp_seconds{groupAddress="0/0/1"} > 1200`, "", "warning"},
+		{"KNXGroupAddressMissing", `absent(knx_last_update_timestamp_seconds{groupAddress="0/0/1"})`, "20m", "warning"},
+		{"KNXValueTooHigh", `knx_temperature{room="Kitchen"} > 30`, "5m", "warning"},
+		{"KNXValueOutOfRange", `knx_temperature{room="Kitchen"} < -273 or knx_temperature{room="Kitchen"} > 670760`, "5m", "warning"},
+		{"KNXAlarm", `knx_smoke_alarm == 1`, "", "critical"},
+		{"knx_energy:increase1h", `increase(knx_energy[1h])`, "", ""},
+	}, rules)
+}
+
+func TestNewRuleFile_Installations(t *testing.T) {
+	config := &knx.Config{
+		Installations: []*knx.Config{{
+			Installation:  "home",
+			MetricsPrefix: "knx_",
+			AddressConfigs: knx.GroupAddressConfigSet{
+				knx.GroupAddress(1): {Name: "temperature", DPT: "9.001", Export: true, ReadActive: true, MaxAge: knx.Duration(time.Minute)},
+			},
+		}},
+	}
+
+	ruleFile := NewRuleFile(config)
+	assert.Len(t, ruleFile.Groups, 1)
+	assert.Equal(t, "knx-home", ruleFile.Groups[0].Name)
+	assert.Len(t, ruleFile.Groups[0].Rules, 2)
+	assert.Equal(t, `time() - knx_last_update_timestamp_seconds{groupAddress="0/0/1", installation="home"} > 120`, ruleFile.Groups[0].Rules[0].Expr)
+	assert.Equal(t, "home", ruleFile.Groups[0].Rules[0].Labels["installation"])
+	assert.Equal(t, `absent(knx_last_update_timestamp_seconds{groupAddress="0/0/1", installation="home"})`, ruleFile.Groups[0].Rules[1].Expr)
+}
+
+func TestNewRuleFile_NotExported(t *testing.T) {
+	config := &knx.Config{
+		MetricsPrefix: "knx_",
+		AddressConfigs: knx.GroupAddressConfigSet{
+			knx.GroupAddress(1): {Name: "temperature", DPT: "9.001", ReadActive: true, MaxAge: knx.Duration(time.Minute), Alerts: &knx.AlertConfig{OutOfRange: true}},
+		},
+	}
+
+	ruleFile := NewRuleFile(config)
+	assert.Empty(t, ruleFile.Groups[0].Rules)
+}
+
+func TestNewRuleFile_SameSeries(t *testing.T) {
+	alarm := &knx.AlertConfig{Alarm: true}
+	config := &knx.Config{
+		MetricsPrefix: "knx_",
+		AddressConfigs: knx.GroupAddressConfigSet{
+			knx.GroupAddress(1): {Name: "alarm", Export: true, Alerts: alarm},
+			knx.GroupAddress(2): {Name: "alarm", Export: true, Alerts: alarm},
+			knx.GroupAddress(3): {Name: "alarm", Export: true, Labels: map[string]string{"room": "Kitchen"}, Alerts: alarm},
+		},
+	}
+
+	ruleFile := NewRuleFile(config)
+	assert.Len(t, ruleFile.Groups[0].Rules, 1)
+	assert.Equal(t, `knx_alarm{room="Kitchen"} == 1`, ruleFile.Groups[0].Rules[0].Expr)
+	assert.Equal(t, "0/0/3", ruleFile.Groups[0].Rules[0].Labels["groupAddress"])
+}
+
+func TestNewRuleFile_ReadWindows(t *testing.T) {
+	workingHours, err := knx.NewTimeWindow("Mon-Fri 07:00-18:00")
+	assert.NoError(t, err)
+	config := &knx.Config{
+		MetricsPrefix: "knx_",
+		AddressConfigs: knx.GroupAddressConfigSet{
+			knx.GroupAddress(1): {Name: "temperature", DPT: "9.001", Export: true, ReadActive: true, MaxAge: knx.Duration(time.Minute), ReadWindows: []knx.TimeWindow{workingHours}},
+		},
+	}
+
+	ruleFile := NewRuleFile(config)
+	assert.Empty(t, ruleFile.Groups[0].Rules)
+}
+
+func Test_rangeFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		dpt    string
+		want   valueRange
+		wantOk bool
+	}{
+		{"specific", "9.001", valueRange{-273, 670760}, true},
+		{"family", "9.005", valueRange{-671088.64, 670760}, true},
+		{"unknown", "1.001", valueRange{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rangeFor(tt.dpt)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/vapourismo/knx-go/knx/dpt"
+
+	"github.com/chr-fritz/knx-exporter/pkg/knx"
+)
+
+const panelWidth = 8
+const panelHeight = 8
+const gridWidth = 24
+
+// Options contains all settings for generating a dashboard.
+type Options struct {
+	// Title of the dashboard.
+	Title string
+	// GroupBy is the name of the label whose values are used to group the panels into rows. i.e. room or floor.
+	GroupBy string
+}
+
+// metric contains all information for a single panel.
+type metric struct {
+	name   string
+	config *knx.GroupAddressConfig
+	// labels contains the names of all labels of all group addresses which are exported with this name.
+	labels []string
+}
+
+// NewDashboard generates a dashboard with one panel for every exported metric of the configuration.
+func NewDashboard(config *knx.Config, options Options) Dashboard {
+	groups, labels, installations := collectMetrics(config, options.GroupBy)
+
+	dashboard := Dashboard{
+		Title:         options.Title,
+		Tags:          []string{"knx"},
+		Timezone:      "browser",
+		SchemaVersion: 39,
+		Time:          TimeRange{From: "now-24h", To: "now"},
+		Refresh:       "1m",
+		Templating:    Templating{List: newVariables(labels, installations)},
+	}
+
+	var groupNames []string
+	for name := range groups {
+		groupNames = append(groupNames, name)
+	}
+	slices.SortFunc(groupNames, func(a, b string) int {
+		// Metrics without the label should be shown at the end.
+		if a == "" || b == "" {
+			return strings.Compare(b, a)
+		}
+		return strings.Compare(a, b)
+	})
+
+	id := 1
+	y := 0
+	for _, groupName := range groupNames {
+		if options.GroupBy != "" {
+			title := groupName
+			if title == "" {
+				title = "Other"
+			}
+			dashboard.Panels = append(dashboard.Panels, Panel{
+				ID:      id,
+				Type:    "row",
+				Title:   title,
+				GridPos: GridPos{H: 1, W: gridWidth, X: 0, Y: y},
+			})
+			id++
+			y++
+		}
+
+		for i, m := range groups[groupName] {
+			panel := newPanel(m, options.GroupBy, groupName, installations)
+			panel.ID = id
+			panel.GridPos = GridPos{H: panelHeight, W: panelWidth, X: (i * panelWidth) % gridWidth, Y: y + (i*panelWidth)/gridWidth*panelHeight}
+			dashboard.Panels = append(dashboard.Panels, panel)
+			id++
+		}
+		y += (len(groups[groupName]) + gridWidth/panelWidth - 1) / (gridWidth / panelWidth) * panelHeight
+	}
+	return dashboard
+}
+
+// collectMetrics collects all exported metrics grouped by the value of the groupBy label. It also returns the names of
+// all static labels and if there are multiple installations.
+func collectMetrics(config *knx.Config, groupBy string) (map[string][]*metric, []string, bool) {
+	groups := make(map[string][]*metric)
+	metrics := make(map[string]*metric)
+	var labels []string
+	installations := len(config.Installations) > 0
+
+	for _, installation := range config.GetInstallations() {
+		var addresses []knx.GroupAddress
+		for address := range installation.AddressConfigs {
+			addresses = append(addresses, address)
+		}
+		slices.Sort(addresses)
+
+		for _, address := range addresses {
+			cfg := installation.AddressConfigs[address]
+			if !cfg.Export {
+				continue
+			}
+			group := cfg.Labels[groupBy]
+			name := installation.NameFor(cfg)
+			key := group + "\x00" + name
+			m, ok := metrics[key]
+			if !ok {
+				m = &metric{name: name, config: cfg}
+				metrics[key] = m
+				groups[group] = append(groups[group], m)
+			}
+			for label := range cfg.Labels {
+				if !slices.Contains(m.labels, label) {
+					m.labels = append(m.labels, label)
+				}
+				if !slices.Contains(labels, label) {
+					labels = append(labels, label)
+				}
+			}
+		}
+	}
+
+	for _, m := range metrics {
+		slices.Sort(m.labels)
+	}
+	slices.Sort(labels)
+	return groups, labels, installations
+}
+
+func newVariables(labels []string, installations bool) []Variable {
+	variables := []Variable{{
+		Name:    "datasource",
+		Label:   "Data source",
+		Type:    "datasource",
+		Query:   "prometheus",
+		Current: map[string]interface{}{},
+	}}
+
+	names := []string{"physicalAddress"}
+	if installations {
+		names = append(names, "installation")
+	}
+	names = append(names, labels...)
+	for _, name := range names {
+		variables = append(variables, Variable{
+			Name:       name,
+			Label:      name,
+			Type:       "query",
+			Datasource: datasourceRef,
+			Query:      fmt.Sprintf("label_values(%s)", name),
+			Refresh:    2,
+			IncludeAll: true,
+			Multi:      true,
+			AllValue:   ".*",
+			Current:    map[string]interface{}{"text": "All", "value": "$__all"},
+		})
+	}
+	return variables
+}
+
+func newPanel(m *metric, groupBy string, group string, installations bool) Panel {
+	selectors := []string{`physicalAddress=~"$physicalAddress"`}
+	if installations {
+		selectors = append(selectors, `installation=~"$installation"`)
+	}
+	legend := []string{"{{physicalAddress}}"}
+	for _, label := range m.labels {
+		if label == groupBy {
+			selectors = append(selectors, fmt.Sprintf("%s=%q", label, group))
+			continue
+		}
+		selectors = append(selectors, fmt.Sprintf("%s=~\"$%s\"", label, label))
+		legend = append(legend, fmt.Sprintf("{{%s}}", label))
+	}
+	expr := fmt.Sprintf("%s{%s}", m.name, strings.Join(selectors, ", "))
+
+	panel := Panel{
+		Type:        "timeseries",
+		Title:       m.config.Name,
+		Description: strings.TrimSpace(m.config.Comment),
+		Datasource:  datasourceRef,
+		FieldConfig: &FieldConfig{Defaults: FieldDefaults{Unit: unitFor(m.config.DPT)}, Overrides: []interface{}{}},
+	}
+
+	family, _, _ := strings.Cut(m.config.DPT, ".")
+	switch {
+	case family == "1":
+		panel.Type = "state-timeline"
+		panel.FieldConfig.Defaults.Unit = ""
+		panel.FieldConfig.Defaults.Mappings = []ValueMapping{{
+			Type: "value",
+			Options: map[string]MappingResult{
+				"0": {Text: "Off", Color: "red", Index: 0},
+				"1": {Text: "On", Color: "green", Index: 1},
+			},
+		}}
+	case strings.ToLower(m.config.MetricType) == "counter":
+		expr = fmt.Sprintf("increase(%s[$__rate_interval])", expr)
+		panel.FieldConfig.Defaults.Custom = map[string]interface{}{"drawStyle": "bars", "fillOpacity": 80}
+	case m.config.DPT == "5.001":
+		panel.Type = "gauge"
+		panel.FieldConfig.Defaults.Min = floatPtr(0)
+		panel.FieldConfig.Defaults.Max = floatPtr(100)
+	}
+
+	panel.Targets = []Target{{
+		RefID:        "A",
+		Datasource:   datasourceRef,
+		Expr:         expr,
+		LegendFormat: strings.Join(legend, " "),
+	}}
+	return panel
+}
+
+// grafanaUnits maps the units of the data point types to the unit ids of Grafana.
+var grafanaUnits = map[string]string{
+	"°C":    "celsius",
+	"°F":    "fahrenheit",
+	"%":     "percent",
+	"lux":   "lux",
+	"W":     "watt",
+	"kW":    "kwatt",
+	"Wh":    "watth",
+	"kWh":   "kwatth",
+	"MWh":   "mwatth",
+	"V":     "volt",
+	"mV":    "mvolt",
+	"A":     "amp",
+	"mA":    "mamp",
+	"Pa":    "pressurepa",
+	"m/s":   "velocityms",
+	"km/h":  "velocitykmh",
+	"ppm":   "ppm",
+	"Hz":    "hertz",
+	"s":     "s",
+	"ms":    "ms",
+	"min":   "m",
+	"h":     "h",
+	"m":     "lengthm",
+	"mm":    "lengthmm",
+	"m³":    "m3",
+	"m³/h":  "flowcmh",
+	"m^3/h": "flowcmh",
+	"l/h":   "litreh",
+	"J":     "joule",
+	"°":     "degree",
+	"kg":    "masskg",
+	"W/m²":  "Wm2",
+	"W/m2":  "Wm2",
+	"VAh":   "voltamp",
+	"kVAh":  "kvoltamp",
+}
+
+// unitFor returns the Grafana unit for the data point type. Units which are unknown to Grafana are used as suffix.
+func unitFor(dataPointType string) string {
+	datapoint, ok := dpt.Produce(dataPointType)
+	if !ok || datapoint.Unit() == "" {
+		return ""
+	}
+	if unit, ok := grafanaUnits[datapoint.Unit()]; ok {
+		return unit
+	}
+	return "suffix:" + datapoint.Unit()
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,101 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chr-fritz/knx-exporter/pkg/knx"
+)
+
+func TestNewDashboard(t *testing.T) {
+	config, err := knx.ReadConfig("fixtures/config.yaml")
+	assert.NoError(t, err)
+
+	dashboard := NewDashboard(config, Options{Title: "Home", GroupBy: "room"})
+	assert.Equal(t, "Home", dashboard.Title)
+
+	var variables []string
+	for _, v := range dashboard.Templating.List {
+		variables = append(variables, v.Name)
+	}
+	assert.Equal(t, []string{"datasource", "physicalAddress", "floor", "room"}, variables)
+
+	type panelSummary struct {
+		Type  string
+		Title string
+		Unit  string
+		Expr  string
+		Pos   GridPos
+	}
+	var panels []panelSummary
+	for _, p := range dashboard.Panels {
+		summary := panelSummary{Type: p.Type, Title: p.Title, Pos: p.GridPos}
+		if p.FieldConfig != nil {
+			summary.Unit = p.FieldConfig.Defaults.Unit
+		}
+		if len(p.Targets) > 0 {
+			summary.Expr = p.Targets[0].Expr
+		}
+		panels = append(panels, summary)
+	}
+	assert.Equal(t, []panelSummary{
+		{"row", "Bath", "", "", GridPos{H: 1, W: 24, X: 0, Y: 0}},
+		{"gauge", "blinds", "percent", `knx_blinds{physicalAddress=~"$physicalAddress", room="Bath"}`, GridPos{H: 8, W: 8, X: 0, Y: 1}},
+		{"timeseries", "temperature", "celsius", `knx_temperature{physicalAddress=~"$physicalAddress", room="Bath"}`, GridPos{H: 8, W: 8, X: 8, Y: 1}},
+		{"row", "Kitchen", "", "", GridPos{H: 1, W: 24, X: 0, Y: 9}},
+		{"timeseries", "temperature", "celsius", `knx_temperature{physicalAddress=~"$physicalAddress", floor=~"$floor", room="Kitchen"}`, GridPos{H: 8, W: 8, X: 0, Y: 10}},
+		{"state-timeline", "light", "", `knx_light{physicalAddress=~"$physicalAddress", room="Kitchen"}`, GridPos{H: 8, W: 8, X: 8, Y: 10}},
+		{"row", "Other", "", "", GridPos{H: 1, W: 24, X: 0, Y: 18}},
+		{"timeseries", "energy", "watth", `increase(knx_energy{physicalAddress=~"$physicalAddress"}[$__rate_interval])`, GridPos{H: 8, W: 8, X: 0, Y: 19}},
+	}, panels)
+}
+
+func TestNewDashboard_WithoutGrouping(t *testing.T) {
+	config, err := knx.ReadConfig("fixtures/config.yaml")
+	assert.NoError(t, err)
+
+	dashboard := NewDashboard(config, Options{Title: "Home"})
+	assert.Len(t, dashboard.Panels, 4)
+	for _, p := range dashboard.Panels {
+		assert.NotEqual(t, "row", p.Type)
+	}
+	assert.Equal(t, GridPos{H: 8, W: 8, X: 0, Y: 8}, dashboard.Panels[3].GridPos)
+	assert.Equal(t, "{{physicalAddress}} {{floor}} {{room}}", dashboard.Panels[0].Targets[0].LegendFormat)
+}
+
+func Test_unitFor(t *testing.T) {
+	tests := []struct {
+		dpt  string
+		want string
+	}{
+		{"9.001", "celsius"},
+		{"13.010", "watth"},
+		{"13.013", "kwatth"},
+		{"1.001", ""},
+		{"9.*", ""},
+		{"14.056", "watt"},
+		{"9.007", "percent"},
+		{"14.007", "degree"},
+		{"13.001", "suffix:pulses"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dpt, func(t *testing.T) {
+			assert.Equal(t, tt.want, unitFor(tt.dpt))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafana
+
+// datasourceRef references the data source selected by the datasource template variable.
+var datasourceRef = &DatasourceRef{Type: "prometheus", UID: "${datasource}"}
+
+// Dashboard is the JSON model of a Grafana dashboard. It only contains the fields which are used by the generator.
+type Dashboard struct {
+	Title         string     `json:"title"`
+	UID           string     `json:"uid,omitempty"`
+	Tags          []string   `json:"tags"`
+	Timezone      string     `json:"timezone"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Time          TimeRange  `json:"time"`
+	Refresh       string     `json:"refresh"`
+	Templating    Templating `json:"templating"`
+	Panels        []Panel    `json:"panels"`
+}
+
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Templating struct {
+	List []Variable `json:"list"`
+}
+
+// Variable is a template variable of the dashboard.
+type Variable struct {
+	Name       string                 `json:"name"`
+	Label      string                 `json:"label,omitempty"`
+	Type       string                 `json:"type"`
+	Datasource *DatasourceRef         `json:"datasource,omitempty"`
+	Query      string                 `json:"query"`
+	Refresh    int                    `json:"refresh,omitempty"`
+	IncludeAll bool                   `json:"includeAll"`
+	Multi      bool                   `json:"multi"`
+	AllValue   string                 `json:"allValue,omitempty"`
+	Current    map[string]interface{} `json:"current"`
+}
+
+type DatasourceRef struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+// Panel is either a visualization or a row of the dashboard.
+type Panel struct {
+	ID          int            `json:"id"`
+	Type        string         `json:"type"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	GridPos     GridPos        `json:"gridPos"`
+	Datasource  *DatasourceRef `json:"datasource,omitempty"`
+	Targets     []Target       `json:"targets,omitempty"`
+	FieldConfig *FieldConfig   `json:"fieldConfig,omitempty"`
+}
+
+type GridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Target is a single Prometheus query of a panel.
+type Target struct {
+	RefID        string         `json:"refId"`
+	Datasource   *DatasourceRef `json:"datasource,omitempty"`
+	Expr         string         `json:"expr"`
+	LegendFormat string         `json:"legendFormat"`
+}
+
+type FieldConfig struct {
+	Defaults  FieldDefaults `json:"defaults"`
+	Overrides []interface{} `json:"overrides"`
+}
+
+type FieldDefaults struct {
+	Unit     string                 `json:"unit,omitempty"`
+	Min      *float64               `json:"min,omitempty"`
+	Max      *float64               `json:"max,omitempty"`
+	Mappings []ValueMapping         `json:"mappings,omitempty"`
+	Custom   map[string]interface{} `json:"custom,omitempty"`
+}
+
+// ValueMapping maps raw values into texts like On and Off.
+type ValueMapping struct {
+	Type    string                   `json:"type"`
+	Options map[string]MappingResult `json:"options"`
+}
+
+type MappingResult struct {
+	Text  string `json:"text"`
+	Color string `json:"color,omitempty"`
+	Index int    `json:"index"`
+}
@@ -0,0 +1,181 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultAlertMinInterval is the minimal time between two writes of the same action if nothing else is configured.
+const defaultAlertMinInterval = time.Minute
+
+// AlertmanagerWebhook is the payload which is sent by the Alertmanager to webhook receivers.
+type AlertmanagerWebhook struct {
+	Version  string              `json:"version"`
+	Receiver string              `json:"receiver"`
+	Status   string              `json:"status"`
+	Alerts   []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert is a single alert of an AlertmanagerWebhook.
+type AlertmanagerAlert struct {
+	// Status is either firing or resolved.
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	Fingerprint string            `json:"fingerprint"`
+}
+
+// alertActionKey identifies the writes of a single action and alert status for the rate limiting.
+type alertActionKey struct {
+	installation string
+	action       int
+	status       string
+}
+
+type alertReceiver struct {
+	exporters  []MetricsExporter
+	lock       sync.Mutex
+	lastWrites map[alertActionKey]time.Time
+	now        func() time.Time
+}
+
+// NewAlertmanagerHandler creates an Alertmanager webhook receiver which writes the values of the configured
+// AlertActions of all exporters. Writes of the same action and alert status are limited to one per MinInterval.
+func NewAlertmanagerHandler(exporters []MetricsExporter) http.Handler {
+	return &alertReceiver{
+		exporters:  exporters,
+		lastWrites: make(map[alertActionKey]time.Time),
+		now:        time.Now,
+	}
+}
+
+func (a *alertReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var webhook AlertmanagerWebhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		http.Error(w, "can not parse alertmanager webhook: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	failed := false
+	for _, exporter := range a.exporters {
+		config := exporter.Config().Alertmanager
+		if config == nil {
+			continue
+		}
+		for _, alert := range webhook.Alerts {
+			for i, action := range config.Actions {
+				if err := a.trigger(exporter, config, i, action, alert); err != nil {
+					failed = true
+				}
+			}
+		}
+	}
+	if failed {
+		// Let the Alertmanager retry the notification.
+		http.Error(w, "can not write all group addresses", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// trigger writes the value of the action if it matches the alert and was not written within the MinInterval.
+func (a *alertReceiver) trigger(exporter MetricsExporter, config *AlertmanagerConfig, index int, action AlertAction, alert AlertmanagerAlert) error {
+	if !action.matches(alert) {
+		return nil
+	}
+	value := action.Firing
+	if alert.Status == "resolved" {
+		value = action.Resolved
+	}
+	if value == "" {
+		return nil
+	}
+	logger := slog.With(
+		"alertname", alert.Labels["alertname"],
+		"status", alert.Status,
+		"address", action.Address.String(),
+		"value", value,
+	)
+	if exporter.Installation() != "" {
+		logger = logger.With("installation", exporter.Installation())
+	}
+
+	key := alertActionKey{installation: exporter.Installation(), action: index, status: alert.Status}
+	minInterval := time.Duration(config.MinInterval)
+	if minInterval == 0 {
+		minInterval = defaultAlertMinInterval
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	now := a.now()
+	if lastWrite, ok := a.lastWrites[key]; ok && now.Sub(lastWrite) < minInterval {
+		logger.Info("Skip alert action as it was triggered recently", "lastWrite", lastWrite)
+		return nil
+	}
+
+	if config.DryRun {
+		logger.Info("Dry run: would write group address value for alert")
+	} else if err := exporter.Write(action.Address, value); err != nil {
+		logger.Warn("Can not write group address value for alert: " + err.Error())
+		return err
+	} else {
+		logger.Info("Wrote group address value for alert")
+	}
+	a.lastWrites[key] = now
+	return nil
+}
+
+func (a AlertAction) matches(alert AlertmanagerAlert) bool {
+	for name, value := range a.Matchers {
+		if alert.Labels[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// validateAlertActions checks that all actions have matchers, their group addresses are configured and their values
+// can be encoded.
+func validateAlertActions(config *Config) error {
+	if config.Alertmanager == nil {
+		return nil
+	}
+	for i, action := range config.Alertmanager.Actions {
+		if len(action.Matchers) == 0 {
+			return fmt.Errorf("alert action %d has no matchers", i)
+		}
+		addressConfig, ok := config.AddressConfigs[action.Address]
+		if !ok {
+			return fmt.Errorf("group address %s of alert action %d is not configured", action.Address, i)
+		}
+		for _, value := range []string{action.Firing, action.Resolved} {
+			if value == "" {
+				continue
+			}
+			if _, err := encodeValue(addressConfig.DPT, value); err != nil {
+				return fmt.Errorf("invalid value of alert action %d: %s", i, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/vapourismo/knx-go/knx"
+	"github.com/vapourismo/knx-go/knx/cemi"
+)
+
+func Test_validateAlertActions(t *testing.T) {
+	addressConfigs := GroupAddressConfigSet{1: {Name: "valve", DPT: "1.001"}}
+	tests := []struct {
+		name    string
+		actions []AlertAction
+		wantErr bool
+	}{
+		{"valid", []AlertAction{{Matchers: map[string]string{"alertname": "WaterLeakage"}, Address: 1, Firing: "off", Resolved: "on"}}, false},
+		{"without values", []AlertAction{{Matchers: map[string]string{"alertname": "WaterLeakage"}, Address: 1}}, false},
+		{"without matchers", []AlertAction{{Address: 1, Firing: "off"}}, true},
+		{"unknown address", []AlertAction{{Matchers: map[string]string{"alertname": "WaterLeakage"}, Address: 2, Firing: "off"}}, true},
+		{"invalid value", []AlertAction{{Matchers: map[string]string{"alertname": "WaterLeakage"}, Address: 1, Resolved: "open"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{AddressConfigs: addressConfigs, Alertmanager: &AlertmanagerConfig{Actions: tt.actions}}
+			if err := validateAlertActions(config); (err != nil) != tt.wantErr {
+				t.Errorf("validateAlertActions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerHandler(t *testing.T) {
+	const leakage = `{"version":"4","status":"%[1]s","alerts":[{"status":"%[1]s","labels":{"alertname":"WaterLeakage","room":"%[2]s"}}]}`
+	type step struct {
+		body       string
+		after      time.Duration
+		sendErr    error
+		wantSend   []byte
+		wantStatus int
+	}
+	tests := []struct {
+		name   string
+		dryRun bool
+		steps  []step
+	}{
+		{"firing and resolved", false, []step{
+			{fmt.Sprintf(leakage, "firing", "bath"), 0, nil, []byte{0}, http.StatusOK},
+			{fmt.Sprintf(leakage, "resolved", "bath"), time.Second, nil, []byte{1}, http.StatusOK},
+		}},
+		{"rate limited", false, []step{
+			{fmt.Sprintf(leakage, "firing", "bath"), 0, nil, []byte{0}, http.StatusOK},
+			{fmt.Sprintf(leakage, "firing", "bath"), 30 * time.Second, nil, nil, http.StatusOK},
+			{fmt.Sprintf(leakage, "firing", "bath"), time.Minute, nil, []byte{0}, http.StatusOK},
+		}},
+		{"not matching", false, []step{
+			{fmt.Sprintf(leakage, "firing", "kitchen"), 0, nil, nil, http.StatusOK},
+		}},
+		{"retry after send error", false, []step{
+			{fmt.Sprintf(leakage, "firing", "bath"), 0, fmt.Errorf("closed"), []byte{0}, http.StatusServiceUnavailable},
+			{fmt.Sprintf(leakage, "firing", "bath"), time.Second, nil, []byte{0}, http.StatusOK},
+		}},
+		{"dry run", true, []step{
+			{fmt.Sprintf(leakage, "firing", "bath"), 0, nil, nil, http.StatusOK},
+		}},
+		{"invalid body", false, []step{
+			{`{"alerts":`, 0, nil, nil, http.StatusBadRequest},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := NewMockGroupClient(ctrl)
+			exporter := &metricsExporter{
+				config: &Config{
+					AddressConfigs: GroupAddressConfigSet{1: {Name: "valve", DPT: "1.001"}},
+					Alertmanager: &AlertmanagerConfig{
+						DryRun: tt.dryRun,
+						Actions: []AlertAction{
+							{Matchers: map[string]string{"alertname": "WaterLeakage", "room": "bath"}, Address: 1, Firing: "off", Resolved: "on"},
+						},
+					},
+				},
+				client:         client,
+				messageCounter: prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"direction", "processed"}),
+			}
+			now := time.Unix(1700000000, 0)
+			handler := NewAlertmanagerHandler([]MetricsExporter{exporter}).(*alertReceiver)
+			handler.now = func() time.Time { return now }
+
+			for _, s := range tt.steps {
+				now = now.Add(s.after)
+				if s.wantSend != nil {
+					client.EXPECT().Send(knx.GroupEvent{Command: knx.GroupWrite, Destination: cemi.GroupAddr(1), Data: s.wantSend}).Return(s.sendErr)
+				}
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/alerts", strings.NewReader(s.body)))
+				assert.Equal(t, s.wantStatus, recorder.Code)
+			}
+		})
+	}
+}
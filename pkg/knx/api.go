@@ -0,0 +1,204 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"time"
+
+	"github.com/vapourismo/knx-go/knx/dpt"
+)
+
+// redactedSecret replaces all secrets within the configuration returned by the api.
+const redactedSecret = "<redacted>"
+
+// ExporterStatus contains the current state of a single KNX installation.
+type ExporterStatus struct {
+	Installation   string               `json:"installation,omitempty"`
+	Connection     ConnectionStatus     `json:"connection"`
+	GroupAddresses []GroupAddressStatus `json:"groupAddresses"`
+	// Warnings are the recent problems while processing the received telegrams. The newest warning is the first.
+	Warnings []Warning `json:"warnings"`
+}
+
+// ConnectionStatus describes the connection to the KNX gateway.
+type ConnectionStatus struct {
+	Installation string         `json:"installation,omitempty"`
+	Type         ConnectionType `json:"type"`
+	Endpoint     string         `json:"endpoint"`
+	Connected    bool           `json:"connected"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// GroupAddressStatus contains the configuration and the latest received value of a single group address.
+type GroupAddressStatus struct {
+	Installation string            `json:"installation,omitempty"`
+	Address      GroupAddress      `json:"address"`
+	Name         string            `json:"name"`
+	Comment      string            `json:"comment,omitempty"`
+	DPT          string            `json:"dpt"`
+	MetricType   string            `json:"metricType"`
+	Export       bool              `json:"export"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	// Value is the latest decoded value. It is empty if no value was received yet.
+	Value *float64 `json:"value,omitempty"`
+	Unit  string   `json:"unit,omitempty"`
+	// LastUpdate is the time when the latest value was received.
+	LastUpdate *time.Time `json:"lastUpdate,omitempty"`
+	// Source is the physical address of the device which has sent the latest value.
+	Source      *PhysicalAddress `json:"source,omitempty"`
+	ReadStartup bool             `json:"readStartup"`
+	ReadActive  bool             `json:"readActive"`
+	MaxAge      Duration         `json:"maxAge,omitempty"`
+	// LastReadRequest is the time when the exporter has sent the last read request for the group address.
+	LastReadRequest *time.Time `json:"lastReadRequest,omitempty"`
+	// Stale is true if the group address is actively read but the latest value is older than MaxAge.
+	Stale bool `json:"stale"`
+}
+
+// NewAPIHandler creates a read-only json api with the configuration, the connection states and the latest values of
+// all group addresses of the given exporters together with the health of their components. Additionally, it streams
+// all received telegrams as server-sent events.
+func NewAPIHandler(exporters []MetricsExporter) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/groupAddresses", func(w http.ResponseWriter, r *http.Request) {
+		addresses := []GroupAddressStatus{}
+		for _, exporter := range filterInstallation(exporters, r) {
+			addresses = append(addresses, exporter.Status().GroupAddresses...)
+		}
+		writeJSON(w, addresses)
+	})
+	mux.HandleFunc("GET /api/v1/connections", func(w http.ResponseWriter, r *http.Request) {
+		connections := []ConnectionStatus{}
+		for _, exporter := range filterInstallation(exporters, r) {
+			connections = append(connections, exporter.Status().Connection)
+		}
+		writeJSON(w, connections)
+	})
+	mux.HandleFunc("GET /api/v1/config", func(w http.ResponseWriter, r *http.Request) {
+		configs := []*Config{}
+		for _, exporter := range filterInstallation(exporters, r) {
+			configs = append(configs, redactSecrets(exporter.Config()))
+		}
+		writeJSON(w, configs)
+	})
+	mux.HandleFunc("GET /api/v1/health", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(filterInstallation(exporters, r), w)
+	})
+	mux.HandleFunc("GET /api/v1/telegrams", func(w http.ResponseWriter, r *http.Request) {
+		streamTelegrams(filterInstallation(exporters, r), w, r)
+	})
+	return mux
+}
+
+// filterInstallation returns the exporters of the installation given by the installation query parameter or all
+// exporters if it is not set.
+func filterInstallation(exporters []MetricsExporter, r *http.Request) []MetricsExporter {
+	installation := r.URL.Query().Get("installation")
+	if installation == "" {
+		return exporters
+	}
+	var filtered []MetricsExporter
+	for _, exporter := range exporters {
+		if exporter.Installation() == installation {
+			filtered = append(filtered, exporter)
+		}
+	}
+	return filtered
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		slog.Warn("Can not write api response: " + err.Error())
+	}
+}
+
+// newGroupAddressStatus returns the status of the group address using the youngest of the given snapshots.
+func newGroupAddressStatus(config *Config, address GroupAddress, snapshots []*Snapshot, poller Poller, now time.Time) GroupAddressStatus {
+	cfg := config.AddressConfigs[address]
+	status := GroupAddressStatus{
+		Installation: config.Installation,
+		Address:      address,
+		Name:         config.NameFor(cfg),
+		Comment:      cfg.Comment,
+		DPT:          cfg.DPT,
+		MetricType:   cfg.MetricType,
+		Export:       cfg.Export,
+		Labels:       cfg.Labels,
+		ReadStartup:  cfg.ReadStartup,
+		ReadActive:   cfg.ReadActive,
+		MaxAge:       cfg.MaxAge,
+	}
+	if datapoint, ok := dpt.Produce(cfg.DPT); ok {
+		status.Unit = datapoint.Unit()
+	}
+	if poller != nil {
+		if lastRead, ok := poller.LastReadRequest(address); ok {
+			status.LastReadRequest = &lastRead
+		}
+	}
+
+	var youngest *Snapshot
+	for _, s := range snapshots {
+		if youngest == nil || youngest.timestamp.Before(s.timestamp) {
+			youngest = s
+		}
+	}
+	if youngest != nil {
+		status.Name = youngest.name
+		status.Value = &youngest.value
+		status.LastUpdate = &youngest.timestamp
+		status.Source = &youngest.source
+	}
+	if cfg.ReadActive && cfg.MaxAge > 0 {
+		status.Stale = youngest == nil || now.Sub(youngest.timestamp) > time.Duration(cfg.MaxAge)
+	}
+	return status
+}
+
+// redactSecrets returns a copy of the configuration without any passwords, tokens and headers of the outputs.
+func redactSecrets(config *Config) *Config {
+	redacted := *config
+	if config.MQTT != nil {
+		mqttConfig := *config.MQTT
+		redact(&mqttConfig.Password)
+		redacted.MQTT = &mqttConfig
+	}
+	if config.InfluxDB != nil {
+		influxConfig := *config.InfluxDB
+		redact(&influxConfig.Token)
+		redact(&influxConfig.Password)
+		redacted.InfluxDB = &influxConfig
+	}
+	if config.OTLP != nil {
+		otlpConfig := *config.OTLP
+		otlpConfig.Headers = maps.Clone(config.OTLP.Headers)
+		for name := range otlpConfig.Headers {
+			otlpConfig.Headers[name] = redactedSecret
+		}
+		redacted.OTLP = &otlpConfig
+	}
+	return &redacted
+}
+
+func redact(secret *string) {
+	if *secret != "" {
+		*secret = redactedSecret
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newGroupAddressStatus(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	config := &Config{
+		MetricsPrefix: "knx_",
+		Installation:  "home",
+		AddressConfigs: GroupAddressConfigSet{
+			1: {Name: "temperature", DPT: "9.001", MetricType: "gauge", Export: true, ReadActive: true, MaxAge: Duration(time.Minute)},
+		},
+	}
+	tests := []struct {
+		name      string
+		snapshots []*Snapshot
+		want      GroupAddressStatus
+	}{
+		{
+			"no value",
+			nil,
+			GroupAddressStatus{Installation: "home", Address: 1, Name: "knx_temperature", DPT: "9.001", MetricType: "gauge", Export: true, Unit: "°C", ReadActive: true, MaxAge: Duration(time.Minute), Stale: true},
+		},
+		{
+			"youngest value",
+			[]*Snapshot{
+				{name: "knx_temperature", source: 0x1105, destination: 1, value: 20, timestamp: now.Add(-2 * time.Minute)},
+				{name: "knx_temperature", source: 0x1106, destination: 1, value: 21.5, timestamp: now.Add(-10 * time.Second)},
+			},
+			GroupAddressStatus{Installation: "home", Address: 1, Name: "knx_temperature", DPT: "9.001", MetricType: "gauge", Export: true, Unit: "°C", ReadActive: true, MaxAge: Duration(time.Minute),
+				Value: ptr(21.5), LastUpdate: ptr(now.Add(-10 * time.Second)), Source: ptr(PhysicalAddress(0x1106))},
+		},
+		{
+			"stale value",
+			[]*Snapshot{
+				{name: "knx_temperature", source: 0x1105, destination: 1, value: 20, timestamp: now.Add(-2 * time.Minute)},
+			},
+			GroupAddressStatus{Installation: "home", Address: 1, Name: "knx_temperature", DPT: "9.001", MetricType: "gauge", Export: true, Unit: "°C", ReadActive: true, MaxAge: Duration(time.Minute),
+				Value: ptr(20.0), LastUpdate: ptr(now.Add(-2 * time.Minute)), Source: ptr(PhysicalAddress(0x1105)), Stale: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newGroupAddressStatus(config, 1, tt.snapshots, nil, now))
+		})
+	}
+}
+
+func TestNewAPIHandler(t *testing.T) {
+	kitchen := &GroupAddressConfig{Name: "temperature", DPT: "9.001", MetricType: "gauge", Export: true}
+	home := &metricsExporter{
+		config: &Config{
+			Installation:   "home",
+			Connection:     Connection{Type: Tunnel, Endpoint: "192.168.1.15:3671"},
+			AddressConfigs: GroupAddressConfigSet{1: kitchen, 2: {Name: "switch", DPT: "1.001"}},
+			MQTT:           &MQTTConfig{Broker: "tcp://localhost:1883", Password: "secret"},
+			OTLP:           &OTLPConfig{Endpoint: "localhost:4317", Headers: map[string]string{"Authorization": "Bearer secret"}},
+		},
+		metrics: NewMetricsSnapshotHandler(1),
+	}
+	home.metrics.AddSnapshot(&Snapshot{name: "temperature", source: 0x1105, destination: 1, value: 21.5, timestamp: time.Now(), config: kitchen})
+	office := &metricsExporter{
+		config:  &Config{Installation: "office", Connection: Connection{Type: Router, Endpoint: "224.0.23.12:3671"}},
+		metrics: NewMetricsSnapshotHandler(1),
+	}
+	server := httptest.NewServer(NewAPIHandler([]MetricsExporter{home, office}))
+	defer server.Close()
+
+	var addresses []GroupAddressStatus
+	get(t, server.URL+"/api/v1/groupAddresses", &addresses)
+	assert.Len(t, addresses, 2)
+	assert.Equal(t, GroupAddress(1), addresses[0].Address)
+	assert.Equal(t, 21.5, *addresses[0].Value)
+	assert.Equal(t, "°C", addresses[0].Unit)
+	assert.Equal(t, PhysicalAddress(0x1105), *addresses[0].Source)
+	assert.Nil(t, addresses[1].Value)
+
+	var connections []ConnectionStatus
+	get(t, server.URL+"/api/v1/connections?installation=office", &connections)
+	assert.Equal(t, []ConnectionStatus{{Installation: "office", Type: Router, Endpoint: "224.0.23.12:3671", Error: "not connected"}}, connections)
+
+	var configs []map[string]any
+	get(t, server.URL+"/api/v1/config?installation=home", &configs)
+	assert.Len(t, configs, 1)
+	assert.Equal(t, "home", configs[0]["Installation"])
+	assert.Equal(t, "<redacted>", configs[0]["MQTT"].(map[string]any)["Password"])
+	assert.Equal(t, map[string]any{"Authorization": "<redacted>"}, configs[0]["OTLP"].(map[string]any)["Headers"])
+	assert.Equal(t, "secret", home.config.MQTT.Password)
+	assert.Equal(t, "Bearer secret", home.config.OTLP.Headers["Authorization"])
+
+	addresses = nil
+	get(t, server.URL+"/api/v1/groupAddresses?installation=unknown", &addresses)
+	assert.Equal(t, []GroupAddressStatus{}, addresses)
+}
+
+func get(t *testing.T, url string, value any) {
+	response, err := http.Get(url)
+	assert.NoError(t, err)
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(value))
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
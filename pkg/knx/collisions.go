@@ -0,0 +1,248 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CollisionStrategy defines how metric name collisions of group addresses are resolved.
+type CollisionStrategy string
+
+// ReportCollisions only reports the collisions without resolving them.
+const ReportCollisions = CollisionStrategy("")
+
+// SuffixAddress appends the group address to the names of all colliding group addresses except the lowest one.
+const SuffixAddress = CollisionStrategy("suffix-ga")
+
+// AddRangeLabels adds the group ranges as labels to all group addresses sharing the name of colliding ones. So all
+// series of the metric have the same labels. Collisions which remain are resolved like SuffixAddress.
+const AddRangeLabels = CollisionStrategy("range-labels")
+
+// FailOnCollision fails if there is any collision.
+const FailOnCollision = CollisionStrategy("fail")
+
+func (s CollisionStrategy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+func (s *CollisionStrategy) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	strategy, err := ParseCollisionStrategy(str)
+	if err != nil {
+		return err
+	}
+	*s = strategy
+	return nil
+}
+
+// ParseCollisionStrategy parses the given string into a CollisionStrategy.
+func ParseCollisionStrategy(str string) (CollisionStrategy, error) {
+	switch strings.ToLower(str) {
+	case "", "report":
+		return ReportCollisions, nil
+	case "suffix-ga":
+		return SuffixAddress, nil
+	case "range-labels":
+		return AddRangeLabels, nil
+	case "fail":
+		return FailOnCollision, nil
+	default:
+		return "", fmt.Errorf("invalid collision strategy given: \"%s\"", str)
+	}
+}
+
+// Collision describes multiple group addresses whose metrics can not be exported together.
+type Collision struct {
+	// Name is the metric name without the MetricsPrefix.
+	Name string
+	// Reason why the group addresses collide.
+	Reason string
+	// Addresses contains all colliding group addresses in ascending order.
+	Addresses []GroupAddress
+	// Resolutions contains what was done to resolve the collision.
+	Resolutions []string
+}
+
+// String returns a short human-readable description of the collision.
+func (c Collision) String() string {
+	var addresses []string
+	for _, address := range c.Addresses {
+		addresses = append(addresses, address.String())
+	}
+	description := fmt.Sprintf("%s (%s): %s", c.Name, c.Reason, strings.Join(addresses, ", "))
+	if len(c.Resolutions) > 0 {
+		description += "; " + strings.Join(c.Resolutions, ", ")
+	}
+	return description
+}
+
+// findCollisions finds all group addresses that would either export the same time series or the same metric with
+// different metric types. If exportedOnly is set, only group addresses with Export enabled are checked.
+func findCollisions(addressConfigs GroupAddressConfigSet, exportedOnly bool) []Collision {
+	var addresses []GroupAddress
+	for address, cfg := range addressConfigs {
+		if !exportedOnly || cfg.Export {
+			addresses = append(addresses, address)
+		}
+	}
+	slices.Sort(addresses)
+
+	byName := make(map[string][]GroupAddress)
+	var names []string
+	for _, address := range addresses {
+		name := addressConfigs[address].Name
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], address)
+	}
+
+	var collisions []Collision
+	for _, name := range names {
+		if len(byName[name]) < 2 {
+			continue
+		}
+		bySeries := make(map[string][]GroupAddress)
+		var series []string
+		metricTypes := make(map[string]bool)
+		for _, address := range byName[name] {
+			cfg := addressConfigs[address]
+			key := seriesKey(cfg.Name, cfg.Labels)
+			if _, ok := bySeries[key]; !ok {
+				series = append(series, key)
+			}
+			bySeries[key] = append(bySeries[key], address)
+			metricTypes[strings.ToLower(cfg.MetricType)] = true
+		}
+		for _, key := range series {
+			if len(bySeries[key]) > 1 {
+				collisions = append(collisions, Collision{Name: name, Reason: "same labels", Addresses: bySeries[key]})
+			}
+		}
+		if len(metricTypes) > 1 {
+			collisions = append(collisions, Collision{Name: name, Reason: "different metric types", Addresses: byName[name]})
+		}
+	}
+	return collisions
+}
+
+// resolveCollisions finds and resolves all collisions using the given strategy. rangeLabels returns the labels of the
+// group ranges for the AddRangeLabels strategy. It returns an error if the strategy is FailOnCollision and there are
+// any collisions.
+func resolveCollisions(addressConfigs GroupAddressConfigSet, strategy CollisionStrategy, exportedOnly bool, rangeLabels func(GroupAddress) map[string]string) ([]Collision, error) {
+	collisions := findCollisions(addressConfigs, exportedOnly)
+	if len(collisions) == 0 {
+		return nil, nil
+	}
+
+	switch strategy {
+	case FailOnCollision:
+		var descriptions []string
+		for _, c := range collisions {
+			descriptions = append(descriptions, c.String())
+		}
+		return collisions, fmt.Errorf("found %d metric name collisions: %s", len(collisions), strings.Join(descriptions, "; "))
+	case AddRangeLabels:
+		for i, c := range collisions {
+			if c.Reason != "same labels" {
+				continue
+			}
+			for _, address := range addressesNamed(addressConfigs, c.Name, exportedOnly) {
+				if addLabels(addressConfigs[address], rangeLabels(address)) {
+					collisions[i].Resolutions = append(collisions[i].Resolutions, fmt.Sprintf("added range labels to %s", address))
+				}
+			}
+		}
+		fallthrough
+	case SuffixAddress:
+		renamed := make(map[GroupAddress]bool)
+		for _, c := range findCollisions(addressConfigs, exportedOnly) {
+			for _, address := range c.Addresses[1:] {
+				if renamed[address] {
+					continue
+				}
+				renamed[address] = true
+				cfg := addressConfigs[address]
+				cfg.Name = cfg.Name + "_" + strings.ReplaceAll(address.String(), "/", "_")
+				for i := range collisions {
+					if collisions[i].Name == c.Name && slices.Contains(collisions[i].Addresses, address) {
+						collisions[i].Resolutions = append(collisions[i].Resolutions, fmt.Sprintf("renamed %s to %s", address, cfg.Name))
+						break
+					}
+				}
+			}
+		}
+	}
+	return collisions, nil
+}
+
+// addressesNamed returns all group addresses with the given metric name in ascending order. If exportedOnly is set,
+// only group addresses with Export enabled are returned.
+func addressesNamed(addressConfigs GroupAddressConfigSet, name string, exportedOnly bool) []GroupAddress {
+	var addresses []GroupAddress
+	for address, cfg := range addressConfigs {
+		if cfg.Name == name && (!exportedOnly || cfg.Export) {
+			addresses = append(addresses, address)
+		}
+	}
+	slices.Sort(addresses)
+	return addresses
+}
+
+// addLabels adds all labels to the group address configuration which are not defined yet. It returns true if any
+// label was added.
+func addLabels(cfg *GroupAddressConfig, labels map[string]string) bool {
+	added := false
+	for name, value := range labels {
+		if _, ok := cfg.Labels[name]; ok {
+			continue
+		}
+		if cfg.Labels == nil {
+			cfg.Labels = make(map[string]string)
+		}
+		cfg.Labels[name] = value
+		added = true
+	}
+	return added
+}
+
+// addressRangeLabel returns the main and middle group of the group address as label. It is used by the AddRangeLabels
+// strategy if no group range names are known.
+func addressRangeLabel(address GroupAddress) map[string]string {
+	return map[string]string{"groupRange": fmt.Sprintf("%d/%d", address>>11&0x1f, address>>8&0x7)}
+}
+
+// seriesKey returns an unique key for the metric name together with its labels.
+func seriesKey(name string, labels map[string]string) string {
+	var names []string
+	for label := range labels {
+		names = append(names, label)
+	}
+	slices.Sort(names)
+
+	var key strings.Builder
+	key.WriteString(name)
+	for _, label := range names {
+		_, _ = fmt.Fprintf(&key, ",%s=%q", label, labels[label])
+	}
+	return key.String()
+}
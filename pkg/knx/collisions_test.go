@@ -0,0 +1,163 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resolveCollisions(t *testing.T) {
+	tests := []struct {
+		name         string
+		configs      GroupAddressConfigSet
+		strategy     CollisionStrategy
+		exportedOnly bool
+		want         GroupAddressConfigSet
+		collisions   []string
+		wantErr      bool
+	}{
+		{
+			"no collisions",
+			GroupAddressConfigSet{1: {Name: "a"}, 2: {Name: "b"}, 3: {Name: "a", Labels: map[string]string{"room": "bath"}}},
+			FailOnCollision,
+			false,
+			GroupAddressConfigSet{1: {Name: "a"}, 2: {Name: "b"}, 3: {Name: "a", Labels: map[string]string{"room": "bath"}}},
+			nil,
+			false,
+		},
+		{
+			"report only",
+			GroupAddressConfigSet{1: {Name: "a"}, 2: {Name: "a"}},
+			ReportCollisions,
+			false,
+			GroupAddressConfigSet{1: {Name: "a"}, 2: {Name: "a"}},
+			[]string{"a (same labels): 0/0/1, 0/0/2"},
+			false,
+		},
+		{
+			"fail",
+			GroupAddressConfigSet{1: {Name: "a"}, 2: {Name: "a"}},
+			FailOnCollision,
+			false,
+			GroupAddressConfigSet{1: {Name: "a"}, 2: {Name: "a"}},
+			[]string{"a (same labels): 0/0/1, 0/0/2"},
+			true,
+		},
+		{
+			"only exported",
+			GroupAddressConfigSet{1: {Name: "a", Export: true}, 2: {Name: "a"}},
+			FailOnCollision,
+			true,
+			GroupAddressConfigSet{1: {Name: "a", Export: true}, 2: {Name: "a"}},
+			nil,
+			false,
+		},
+		{
+			"suffix group address",
+			GroupAddressConfigSet{1: {Name: "a"}, 2: {Name: "a"}, 3: {Name: "a"}},
+			SuffixAddress,
+			false,
+			GroupAddressConfigSet{1: {Name: "a"}, 2: {Name: "a_0_0_2"}, 3: {Name: "a_0_0_3"}},
+			[]string{"a (same labels): 0/0/1, 0/0/2, 0/0/3; renamed 0/0/2 to a_0_0_2, renamed 0/0/3 to a_0_0_3"},
+			false,
+		},
+		{
+			"different metric types",
+			GroupAddressConfigSet{1: {Name: "a", MetricType: "gauge"}, 2: {Name: "a", MetricType: "counter", Labels: map[string]string{"room": "bath"}}},
+			SuffixAddress,
+			false,
+			GroupAddressConfigSet{1: {Name: "a", MetricType: "gauge"}, 2: {Name: "a_0_0_2", MetricType: "counter", Labels: map[string]string{"room": "bath"}}},
+			[]string{"a (different metric types): 0/0/1, 0/0/2; renamed 0/0/2 to a_0_0_2"},
+			false,
+		},
+		{
+			"range labels",
+			GroupAddressConfigSet{1: {Name: "a"}, 257: {Name: "a"}},
+			AddRangeLabels,
+			false,
+			GroupAddressConfigSet{1: {Name: "a", Labels: map[string]string{"groupRange": "0/0"}}, 257: {Name: "a", Labels: map[string]string{"groupRange": "0/1"}}},
+			[]string{"a (same labels): 0/0/1, 0/1/1; added range labels to 0/0/1, added range labels to 0/1/1"},
+			false,
+		},
+		{
+			"range labels for all addresses with the same name",
+			GroupAddressConfigSet{1: {Name: "a", Export: true}, 257: {Name: "a", Export: true}, 513: {Name: "a", Export: true, Labels: map[string]string{"room": "bath"}}, 769: {Name: "a"}},
+			AddRangeLabels,
+			true,
+			GroupAddressConfigSet{
+				1:   {Name: "a", Export: true, Labels: map[string]string{"groupRange": "0/0"}},
+				257: {Name: "a", Export: true, Labels: map[string]string{"groupRange": "0/1"}},
+				513: {Name: "a", Export: true, Labels: map[string]string{"room": "bath", "groupRange": "0/2"}},
+				769: {Name: "a"},
+			},
+			[]string{"a (same labels): 0/0/1, 0/1/1; added range labels to 0/0/1, added range labels to 0/1/1, added range labels to 0/2/1"},
+			false,
+		},
+		{
+			"range labels with fallback",
+			GroupAddressConfigSet{1: {Name: "a"}, 2: {Name: "a"}},
+			AddRangeLabels,
+			false,
+			GroupAddressConfigSet{1: {Name: "a", Labels: map[string]string{"groupRange": "0/0"}}, 2: {Name: "a_0_0_2", Labels: map[string]string{"groupRange": "0/0"}}},
+			[]string{"a (same labels): 0/0/1, 0/0/2; added range labels to 0/0/1, added range labels to 0/0/2, renamed 0/0/2 to a_0_0_2"},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collisions, err := resolveCollisions(tt.configs, tt.strategy, tt.exportedOnly, addressRangeLabel)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveCollisions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var descriptions []string
+			for _, c := range collisions {
+				descriptions = append(descriptions, c.String())
+			}
+			assert.Equal(t, tt.collisions, descriptions)
+			assert.Equal(t, tt.want, tt.configs)
+		})
+	}
+}
+
+func TestCollisionStrategy_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    CollisionStrategy
+		wantErr bool
+	}{
+		{"\"\"", ReportCollisions, false},
+		{"\"report\"", ReportCollisions, false},
+		{"\"Suffix-GA\"", SuffixAddress, false},
+		{"\"range-labels\"", AddRangeLabels, false},
+		{"\"fail\"", FailOnCollision, false},
+		{"\"rename\"", "", true},
+		{"1", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			var strategy CollisionStrategy
+			err := json.Unmarshal([]byte(tt.data), &strategy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, strategy)
+		})
+	}
+}
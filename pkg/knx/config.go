@@ -15,8 +15,12 @@
 package knx
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"maps"
 	"net"
 	"os"
 	"strings"
@@ -35,6 +39,31 @@ type Config struct {
 	AddressConfigs GroupAddressConfigSet
 	// ReadStartupInterval is the intervall to wait between read of group addresses after startup.
 	ReadStartupInterval Duration `json:",omitempty"`
+	// SnapshotQueue configures the buffer between receiving telegrams and storing them as metric snapshots.
+	SnapshotQueue SnapshotQueueConfig
+	// Installation is the name of the KNX installation. If set, it is added as `installation` label to all metrics.
+	Installation string `json:",omitempty"`
+	// Installations defines multiple independent KNX installations which are exported by a single process. All
+	// settings of this configuration are used as defaults for every installation.
+	Installations []*Config `json:",omitempty"`
+	// Collisions defines how group addresses with the same metric name and labels or with different metric types for
+	// the same metric name are handled. Possible values are suffix-ga, range-labels and fail. By default, they are
+	// only reported.
+	Collisions CollisionStrategy `json:",omitempty"`
+	// MQTT configures publishing of all received values of the exported group addresses to a MQTT broker.
+	MQTT *MQTTConfig `json:",omitempty"`
+	// InfluxDB configures writing all received values of the exported group addresses into an InfluxDB.
+	InfluxDB *InfluxDBConfig `json:",omitempty"`
+	// OTLP configures exporting all received values of the exported group addresses as OpenTelemetry metrics.
+	OTLP *OTLPConfig `json:",omitempty"`
+	// Alertmanager configures the group writes which are triggered by alerts of the Prometheus Alertmanager.
+	Alertmanager *AlertmanagerConfig `json:",omitempty"`
+}
+
+// defaultSnapshotQueueConfig contains the SnapshotQueueConfig which is used if nothing else is configured.
+var defaultSnapshotQueueConfig = SnapshotQueueConfig{
+	Size:           256,
+	OverflowPolicy: DropOldest,
 }
 
 // ReadConfig reads the given configuration file and returns the parsed Config object.
@@ -58,14 +87,124 @@ func ReadConfig(configFile string) (*Config, error) {
 				UseTCP:            false,
 			},
 		},
+		SnapshotQueue: defaultSnapshotQueueConfig,
 	}
 	err = yaml.Unmarshal(content, &config)
 	if err != nil {
 		return nil, fmt.Errorf("can not read config file %s: %s", configFile, err)
 	}
+	if len(config.Installations) > 0 {
+		if err = config.readInstallations(content); err != nil {
+			return nil, fmt.Errorf("can not read installations from config file %s: %s", configFile, err)
+		}
+	}
+
+	for _, installation := range config.GetInstallations() {
+		if installation.SnapshotQueue.Size == 0 {
+			return nil, fmt.Errorf("invalid config file %s: the SnapshotQueue Size must be greater than 0", configFile)
+		}
+		if err = installation.resolveCollisions(); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %s", configFile, err)
+		}
+	}
 	return &config, nil
 }
 
+// resolveCollisions reports and resolves all metric name collisions of the exported group addresses using the
+// configured strategy.
+func (c *Config) resolveCollisions() error {
+	collisions, err := resolveCollisions(c.AddressConfigs, c.Collisions, true, addressRangeLabel)
+	if err != nil {
+		return err
+	}
+	for _, collision := range collisions {
+		slog.Warn("Metric name collision: "+collision.String(), "installation", c.Installation, "strategy", c.Collisions)
+	}
+	return nil
+}
+
+// readInstallations parses all installations again using the already parsed root configuration as default values.
+// This allows to define common settings like the MetricsPrefix once for all installations.
+func (c *Config) readInstallations(content []byte) error {
+	if len(c.AddressConfigs) > 0 {
+		return fmt.Errorf("AddressConfigs must be defined within the installations if Installations are used")
+	}
+
+	var raw struct {
+		Installations []json.RawMessage
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool)
+	for i, rawInstallation := range raw.Installations {
+		installation := c.cloneDefaults()
+		if err := json.Unmarshal(rawInstallation, &installation); err != nil {
+			return err
+		}
+		if installation.Installation == "" {
+			return fmt.Errorf("installation %d has no name", i)
+		}
+		if names[installation.Installation] {
+			return fmt.Errorf("installation name \"%s\" is used more than once", installation.Installation)
+		}
+		if len(installation.Installations) > 0 {
+			return fmt.Errorf("installation \"%s\" must not contain further installations", installation.Installation)
+		}
+		names[installation.Installation] = true
+		c.Installations[i] = &installation
+	}
+	return nil
+}
+
+// cloneDefaults returns a deep copy of the root configuration without its installations. The outputs and the
+// Alertmanager configuration are copied as well, so an installation can override them without affecting the others.
+func (c *Config) cloneDefaults() Config {
+	clone := *c
+	clone.Installation = ""
+	clone.Installations = nil
+	if c.MQTT != nil {
+		mqttConfig := *c.MQTT
+		mqttConfig.TLS = c.MQTT.TLS.clone()
+		clone.MQTT = &mqttConfig
+	}
+	if c.InfluxDB != nil {
+		influxConfig := *c.InfluxDB
+		if c.InfluxDB.MaxRetries != nil {
+			maxRetries := *c.InfluxDB.MaxRetries
+			influxConfig.MaxRetries = &maxRetries
+		}
+		influxConfig.TLS = c.InfluxDB.TLS.clone()
+		clone.InfluxDB = &influxConfig
+	}
+	if c.OTLP != nil {
+		otlpConfig := *c.OTLP
+		otlpConfig.Headers = maps.Clone(c.OTLP.Headers)
+		otlpConfig.TLS = c.OTLP.TLS.clone()
+		clone.OTLP = &otlpConfig
+	}
+	if c.Alertmanager != nil {
+		alertmanagerConfig := *c.Alertmanager
+		alertmanagerConfig.Actions = make([]AlertAction, len(c.Alertmanager.Actions))
+		for i, action := range c.Alertmanager.Actions {
+			action.Matchers = maps.Clone(action.Matchers)
+			alertmanagerConfig.Actions[i] = action
+		}
+		clone.Alertmanager = &alertmanagerConfig
+	}
+	return clone
+}
+
+// GetInstallations returns all KNX installations defined within this configuration. If no Installations are defined,
+// the configuration itself is the only installation.
+func (c *Config) GetInstallations() []*Config {
+	if len(c.Installations) == 0 {
+		return []*Config{c}
+	}
+	return c.Installations
+}
+
 // NameForGa returns the full metric name for the given GroupAddress.
 func (c *Config) NameForGa(address GroupAddress) string {
 	gaConfig, ok := c.AddressConfigs[address]
@@ -197,6 +336,227 @@ func (d *Duration) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// SnapshotQueueConfig defines the size of the snapshot queue and what should happen if it is full.
+type SnapshotQueueConfig struct {
+	// Size is the number of snapshots that can be buffered until the OverflowPolicy applies. It must be greater than 0.
+	Size uint
+	// OverflowPolicy defines what happens with new snapshots if the queue is full.
+	OverflowPolicy OverflowPolicy
+}
+
+type OverflowPolicy string
+
+// DropOldest discards the oldest queued snapshot to make room for the new one.
+const DropOldest = OverflowPolicy("DropOldest")
+
+// DropNewest discards the new snapshot and keeps the already queued ones.
+const DropNewest = OverflowPolicy("DropNewest")
+
+// Block waits until the queue has room for the new snapshot.
+const Block = OverflowPolicy("Block")
+
+func (p OverflowPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(p))
+}
+
+func (p *OverflowPolicy) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	switch strings.ToLower(str) {
+	case "dropoldest":
+		*p = DropOldest
+	case "dropnewest":
+		*p = DropNewest
+	case "block":
+		*p = Block
+	default:
+		return fmt.Errorf("invalid overflow policy given: \"%s\"", str)
+	}
+	return nil
+}
+
+// MQTTConfig defines the MQTT broker to which all received values are published.
+type MQTTConfig struct {
+	// Broker is the URL of the MQTT broker. i.e. tcp://localhost:1883 or ssl://localhost:8883
+	Broker string
+	// ClientID identifies the exporter at the broker. Default is knx-exporter with the installation name as suffix.
+	ClientID string `json:",omitempty"`
+	// Username for authenticating at the broker.
+	Username string `json:",omitempty"`
+	// Password for authenticating at the broker.
+	Password string `json:",omitempty"`
+	// TopicPrefix is the prefix of the topics of all group addresses without MQTTTopic. The group address is appended
+	// to it. Default is knx or knx/<installation> if the installation has a name.
+	TopicPrefix string `json:",omitempty"`
+	// QoS is the quality of service level of all published messages. Can be 0, 1 or 2.
+	QoS byte `json:",omitempty"`
+	// Retain defines if the broker should retain the last published message of every topic.
+	Retain bool `json:",omitempty"`
+	// BufferSize is the number of values which can be buffered while the broker is not reachable. Default is 256.
+	BufferSize uint `json:",omitempty"`
+	// TLS configures the certificates for connecting to the broker using TLS.
+	TLS *TLSConfig `json:",omitempty"`
+	// HomeAssistantDiscovery publishes Home Assistant discovery messages for all exported group addresses.
+	HomeAssistantDiscovery bool `json:",omitempty"`
+	// DiscoveryPrefix is the topic prefix of the Home Assistant discovery messages. Default is homeassistant.
+	DiscoveryPrefix string `json:",omitempty"`
+}
+
+// InfluxDBConfig defines the InfluxDB into which all received values are written using the line protocol.
+type InfluxDBConfig struct {
+	// URL is the base URL of the InfluxDB. i.e. http://localhost:8086
+	URL string
+	// Version of the write API. Can be either 1 or 2. Default is 2.
+	Version uint `json:",omitempty"`
+	// Token authenticates at the v2 write API.
+	Token string `json:",omitempty"`
+	// Org is the organization of the Bucket for the v2 write API.
+	Org string `json:",omitempty"`
+	// Bucket into which the values are written using the v2 write API.
+	Bucket string `json:",omitempty"`
+	// Database into which the values are written using the v1 write API.
+	Database string `json:",omitempty"`
+	// RetentionPolicy of the Database for the v1 write API. If empty, the default retention policy is used.
+	RetentionPolicy string `json:",omitempty"`
+	// Username for authenticating at the v1 write API.
+	Username string `json:",omitempty"`
+	// Password for authenticating at the v1 write API.
+	Password string `json:",omitempty"`
+	// BatchSize is the maximum number of values which are written at once. Default is 500.
+	BatchSize uint `json:",omitempty"`
+	// FlushInterval is the maximum time until the collected values are written. Default is 10s.
+	FlushInterval Duration `json:",omitempty"`
+	// Timeout of a single write request. Default is 10s.
+	Timeout Duration `json:",omitempty"`
+	// MaxRetries is the number of retries of a failed write until the batch is buffered. Default is 3.
+	MaxRetries *uint `json:",omitempty"`
+	// BufferDir is the directory where batches are stored while the InfluxDB is not reachable. They are written as
+	// soon as it is reachable again. If empty, these batches are dropped.
+	BufferDir string `json:",omitempty"`
+	// MaxBufferedBatches is the maximum number of batches within the BufferDir. If exceeded, the oldest batches are
+	// dropped. Default is 1000.
+	MaxBufferedBatches uint `json:",omitempty"`
+	// TLS configures the certificates for connecting to the InfluxDB using https.
+	TLS *TLSConfig `json:",omitempty"`
+}
+
+// OTLPConfig defines the OpenTelemetry collector to which all received values are exported using OTLP.
+type OTLPConfig struct {
+	// Endpoint is the host and port of the collector. i.e. localhost:4317 for grpc or localhost:4318 for http
+	Endpoint string
+	// Protocol is either grpc or http. Default is grpc.
+	Protocol OTLPProtocol `json:",omitempty"`
+	// URLPath overrides the path of the http endpoint. Default is /v1/metrics.
+	URLPath string `json:",omitempty"`
+	// Insecure disables TLS for the connection to the collector.
+	Insecure bool `json:",omitempty"`
+	// Headers are sent with every export request. i.e. for authentication
+	Headers map[string]string `json:",omitempty"`
+	// Interval between two exports of the latest values. Default is 60s.
+	Interval Duration `json:",omitempty"`
+	// Timeout of a single export request including its retries. Default is 10s.
+	Timeout Duration `json:",omitempty"`
+	// TLS configures the certificates for connecting to the collector.
+	TLS *TLSConfig `json:",omitempty"`
+}
+
+// AlertmanagerConfig defines the actions which are triggered by the alerts received using the Alertmanager webhook.
+type AlertmanagerConfig struct {
+	// DryRun only logs the group writes instead of sending them.
+	DryRun bool `json:",omitempty"`
+	// MinInterval is the minimal time between two group writes of the same action and alert status. Default is 1m.
+	MinInterval Duration `json:",omitempty"`
+	// Actions maps the alerts to group writes.
+	Actions []AlertAction
+}
+
+// AlertAction writes a value to a group address if an alert with matching labels is firing or resolved.
+type AlertAction struct {
+	// Matchers are labels which must be equal on the alert. i.e. alertname: WaterLeakage
+	Matchers map[string]string
+	// Address is the group address which is written. It must be defined within the AddressConfigs as its DPT is
+	// used to encode the values.
+	Address GroupAddress
+	// Firing is the human-readable value which is written if the alert is firing. Nothing is written if empty.
+	Firing string `json:",omitempty"`
+	// Resolved is the human-readable value which is written if the alert is resolved. Nothing is written if empty.
+	Resolved string `json:",omitempty"`
+}
+
+// OTLPProtocol defines the transport which is used to export the OpenTelemetry metrics.
+type OTLPProtocol string
+
+const OTLPGRPC = OTLPProtocol("grpc")
+const OTLPHTTP = OTLPProtocol("http")
+
+func (p OTLPProtocol) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(p))
+}
+
+func (p *OTLPProtocol) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	switch strings.ToLower(str) {
+	case "", "grpc":
+		*p = OTLPGRPC
+	case "http", "http/protobuf":
+		*p = OTLPHTTP
+	default:
+		return fmt.Errorf("invalid otlp protocol given: \"%s\"", str)
+	}
+	return nil
+}
+
+// TLSConfig defines the certificates for a TLS connection.
+type TLSConfig struct {
+	// CAFile is the PEM encoded certificate authority which is used to verify the server certificate. If empty, the
+	// system certificate pool is used.
+	CAFile string `json:",omitempty"`
+	// CertFile is the PEM encoded client certificate for client certificate authentication.
+	CertFile string `json:",omitempty"`
+	// KeyFile is the PEM encoded private key of the client certificate.
+	KeyFile string `json:",omitempty"`
+	// InsecureSkipVerify disables the verification of the server certificate.
+	InsecureSkipVerify bool `json:",omitempty"`
+}
+
+func (c *TLSConfig) clone() *TLSConfig {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	return &clone
+}
+
+func (c *TLSConfig) toTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CAFile != "" {
+		ca, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("can not read ca file: %s", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("can not find any certificate within ca file %s", c.CAFile)
+		}
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("can not load client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
 type ReadType string
 
 const GroupRead = ReadType("GroupRead")
@@ -246,10 +606,38 @@ type GroupAddressConfig struct {
 	ReadBody []byte `json:",omitempty"`
 	// MaxAge of a value until it will actively send a `GroupValueRead` telegram to read the value if ReadActive is set to true.
 	MaxAge Duration `json:",omitempty"`
+	// ReadSchedule is a cron expression at which the exporter actively sends `GroupValueRead` telegrams independent of the MaxAge. i.e. `0 * * * *` for every full hour.
+	ReadSchedule *CronSchedule `json:",omitempty"`
+	// ReadWindows limits the active reading by ReadActive and ReadSchedule to the given time windows. i.e. `Mon-Fri 07:00-18:00`
+	ReadWindows []TimeWindow `json:",omitempty"`
 	// Labels defines static labels that should be set when exporting the metric using prometheus.
 	Labels map[string]string `json:",omitempty"`
 	// WithTimestamp defines if the exported metric should include the timestamp of receiving the last value.
 	WithTimestamp bool
+	// Vanished is set by convertGA if the group address was not part of the group address export anymore.
+	Vanished bool `json:",omitempty"`
+	// Alerts defines additional Prometheus alerts for this group address which are generated by the rules command.
+	Alerts *AlertConfig `json:",omitempty"`
+	// MQTTTopic overrides the topic to which the values are published if MQTT is configured.
+	MQTTTopic string `json:",omitempty"`
+	// Writable allows sending values to this group address using the write api.
+	Writable bool `json:",omitempty"`
+}
+
+// AlertConfig defines the threshold alerts for a single group address.
+type AlertConfig struct {
+	// Alarm creates an alert if the value is 1. i.e. for wind, rain or smoke alarms.
+	Alarm bool `json:",omitempty"`
+	// Min creates an alert if the value is lower than Min.
+	Min *float64 `json:",omitempty"`
+	// Max creates an alert if the value is greater than Max.
+	Max *float64 `json:",omitempty"`
+	// OutOfRange creates an alert if the value is outside the plausible range of the DPT.
+	OutOfRange bool `json:",omitempty"`
+	// For defines how long the condition must be fulfilled until the alert fires.
+	For Duration `json:",omitempty"`
+	// Severity is added as severity label to all alerts. Default is warning.
+	Severity string `json:",omitempty"`
 }
 
 // GroupAddressConfigSet is a shortcut type for the group address config map.
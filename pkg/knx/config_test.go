@@ -51,22 +51,31 @@ func TestReadConfig(t *testing.T) {
 				},
 			},
 			MetricsPrefix: "knx_",
+			SnapshotQueue: SnapshotQueueConfig{Size: 256, OverflowPolicy: DropOldest},
 			AddressConfigs: map[GroupAddress]*GroupAddressConfig{
 				1: {
-					Name:        "dummy_metric",
-					DPT:         "1.*",
-					MetricType:  "counter",
-					Export:      true,
-					ReadActive:  true,
-					MaxAge:      Duration(10 * time.Minute),
-					ReadStartup: true,
-					ReadType:    WriteOther,
-					ReadAddress: 2,
-					ReadBody:    []byte{1},
+					Name:         "dummy_metric",
+					DPT:          "1.*",
+					MetricType:   "counter",
+					Export:       true,
+					ReadActive:   true,
+					MaxAge:       Duration(10 * time.Minute),
+					ReadStartup:  true,
+					ReadType:     WriteOther,
+					ReadAddress:  2,
+					ReadBody:     []byte{1},
+					ReadSchedule: mustCronSchedule(t, "0 * * * *"),
+					ReadWindows: []TimeWindow{{
+						Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+						Start:    7 * time.Hour,
+						End:      18 * time.Hour,
+					}},
 				},
 			},
 		}, false},
 		{"converted config", "fixtures/ga-config.yaml", nil, true},
+		{"duplicated installation", "fixtures/installations-duplicated-config.yaml", nil, true},
+		{"empty snapshot queue", "fixtures/snapshot-queue-empty-config.yaml", nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -80,6 +89,62 @@ func TestReadConfig(t *testing.T) {
 	}
 }
 
+func mustCronSchedule(t *testing.T, expression string) *CronSchedule {
+	schedule, err := NewCronSchedule(expression)
+	assert.NoError(t, err)
+	return schedule
+}
+
+func TestReadConfig_Collisions(t *testing.T) {
+	config, err := ReadConfig("fixtures/collisions-config.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, SuffixAddress, config.Collisions)
+	assert.Equal(t, "temperature", config.AddressConfigs[1].Name)
+	assert.Equal(t, "temperature_0_1_1", config.AddressConfigs[257].Name)
+	assert.Equal(t, "temperature", config.AddressConfigs[513].Name)
+}
+
+func TestReadConfig_Installations(t *testing.T) {
+	config, err := ReadConfig("fixtures/installations-config.yaml")
+	assert.NoError(t, err)
+
+	installations := config.GetInstallations()
+	assert.Len(t, installations, 2)
+
+	assert.Equal(t, "building-a", installations[0].Installation)
+	assert.Equal(t, "knx_", installations[0].MetricsPrefix)
+	assert.Equal(t, Duration(500*time.Millisecond), installations[0].ReadStartupInterval)
+	assert.Equal(t, "192.168.1.15:3671", installations[0].Connection.Endpoint)
+	assert.Equal(t, 10*time.Second, installations[0].Connection.TunnelConfig.HeartbeatInterval)
+	assert.Equal(t, GroupAddressConfigSet{1: {Name: "temperature", DPT: "9.001", Export: true}}, installations[0].AddressConfigs)
+
+	assert.Equal(t, "building-b", installations[1].Installation)
+	assert.Equal(t, "site_b_", installations[1].MetricsPrefix)
+	assert.Equal(t, Router, installations[1].Connection.Type)
+	assert.Equal(t, GroupAddressConfigSet{1: {Name: "temperature", DPT: "9.001", Export: true}}, installations[1].AddressConfigs)
+
+	config, err = ReadConfig("fixtures/installations-outputs-config.yaml")
+	assert.NoError(t, err)
+	installations = config.GetInstallations()
+	assert.Len(t, installations, 2)
+
+	assert.Equal(t, "knx", config.MQTT.TopicPrefix)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer secret"}, config.OTLP.Headers)
+	assert.Equal(t, "site-a", installations[0].MQTT.TopicPrefix)
+	assert.Equal(t, "tcp://localhost:1883", installations[0].MQTT.Broker)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer secret", "X-Site": "a"}, installations[0].OTLP.Headers)
+	assert.Equal(t, "site-b", installations[1].MQTT.TopicPrefix)
+	assert.Equal(t, "tcp://localhost:1883", installations[1].MQTT.Broker)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer secret"}, installations[1].OTLP.Headers)
+	assert.NotSame(t, installations[0].MQTT, installations[1].MQTT)
+	assert.NotSame(t, config.OTLP, installations[1].OTLP)
+}
+
+func TestConfig_GetInstallations(t *testing.T) {
+	config := &Config{MetricsPrefix: "knx_"}
+	assert.Equal(t, []*Config{config}, config.GetInstallations())
+}
+
 func TestConfig_NameForGa(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -15,11 +15,11 @@
 package knx
 
 import (
-	"encoding/xml"
 	"fmt"
 	"log/slog"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/chr-fritz/knx-exporter/pkg/knx/export"
@@ -27,24 +27,104 @@ import (
 	"github.com/ghodss/yaml"
 )
 
-func ConvertGroupAddresses(src string, target string) error {
-	addressExport, err := parseExport(src)
+// ConvertOptions contains all additional settings for converting group address exports into a configuration.
+type ConvertOptions struct {
+	// ProjectPassword is the password to open password protected ETS project files.
+	ProjectPassword string
+	// Merge the converted group addresses into an existing configuration instead of overwriting it.
+	Merge bool
+	// Vanished defines how to handle group addresses while merging that are not part of the export anymore.
+	Vanished VanishedPolicy
+	// Rules defines how Export, MetricType, ReadActive, MaxAge and Labels are derived from the export. If nil, all
+	// group addresses are converted without any defaults.
+	Rules *ConvertRules
+	// RangeLabels overwrites ConvertRules.RangeLabels if not nil.
+	RangeLabels []string
+	// RangePrefix overwrites ConvertRules.RangePrefix if not nil.
+	RangePrefix []int
+	// Collisions overwrites ConvertRules.Collisions if it is not ReportCollisions.
+	Collisions CollisionStrategy
+	// Filter selects the group addresses which should be converted. If nil, all group addresses are converted.
+	Filter *ConvertFilter
+}
+
+// effectiveRules returns the Rules with the RangeLabels and RangePrefix options applied. It returns nil if neither
+// rules nor any of these options are set.
+func (o ConvertOptions) effectiveRules() (*ConvertRules, error) {
+	if o.Rules == nil && o.RangeLabels == nil && o.RangePrefix == nil {
+		return nil, nil
+	}
+	rules := &ConvertRules{}
+	if o.Rules != nil {
+		*rules = *o.Rules
+	}
+	if o.RangeLabels != nil {
+		rules.RangeLabels = o.RangeLabels
+	}
+	if o.RangePrefix != nil {
+		rules.RangePrefix = o.RangePrefix
+	}
+	if err := rules.init(); err != nil {
+		return nil, fmt.Errorf("invalid convert options: %s", err)
+	}
+	return rules, nil
+}
+
+// ConvertGroupAddresses converts the group addresses from the given group address export into a configuration and
+// writes it into target. The source can be either the XML or CSV group address export of the ETS 5 and ETS 6 or the
+// ETS project file (.knxproj). The format is detected automatically.
+func ConvertGroupAddresses(src string, target string, options ConvertOptions) (ConvertSummary, error) {
+	rules, err := options.effectiveRules()
+	if err != nil {
+		return ConvertSummary{}, err
+	}
+	addressExport, err := parseExport(src, options)
 	if err != nil {
-		return err
+		return ConvertSummary{}, err
 	}
 
 	groupAddresses := collectGroupAddresses(addressExport.GroupRange)
+	var filtered map[GroupAddress]bool
+	if options.Filter != nil {
+		if err = options.Filter.init(); err != nil {
+			return ConvertSummary{}, fmt.Errorf("invalid convert filter: %s", err)
+		}
+		groupAddresses, filtered = options.Filter.apply(groupAddresses)
+	}
+
+	addressConfigs := convertAddresses(groupAddresses, rules)
+
+	strategy := options.Collisions
+	if strategy == ReportCollisions && rules != nil {
+		strategy = rules.Collisions
+	}
+	collisions, err := resolveCollisions(addressConfigs, strategy, false, convertedRangeLabels(groupAddresses, rules))
+	if err != nil {
+		return ConvertSummary{Collisions: collisions}, err
+	}
+
+	if options.Merge {
+		cfg, summary, err := mergeIntoConfig(addressConfigs, target, options.Vanished, filtered)
+		if err != nil {
+			return ConvertSummary{}, err
+		}
+		summary.Collisions = collisions
+		return summary, writeConfig(cfg, target)
+	}
 
-	addressConfigs := convertAddresses(groupAddresses)
 	cfg := Config{
 		AddressConfigs: addressConfigs,
 		MetricsPrefix:  "knx_",
+		SnapshotQueue:  defaultSnapshotQueueConfig,
 	}
-
-	return writeConfig(cfg, target)
+	summary := ConvertSummary{Collisions: collisions}
+	for address := range addressConfigs {
+		summary.Added = append(summary.Added, address)
+	}
+	return summary, writeConfig(cfg, target)
 }
 
-func writeConfig(cfg Config, target string) error {
+func writeConfig(cfg interface{}, target string) error {
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("can not marshal config: %s", err)
@@ -62,34 +142,46 @@ func writeConfig(cfg Config, target string) error {
 	return nil
 }
 
-func parseExport(src string) (export.GroupAddressExport, error) {
-	source, err := os.Open(src)
+func parseExport(src string, options ConvertOptions) (export.GroupAddressExport, error) {
+	content, err := os.ReadFile(src)
 	if err != nil {
 		return export.GroupAddressExport{}, fmt.Errorf("can not open source file '%s': %s", src, err)
 	}
-	defer utils.Close(source)
 
-	decoder := xml.NewDecoder(source)
-	addressExport := export.GroupAddressExport{}
-	err = decoder.Decode(&addressExport)
-	if err != nil {
-		return export.GroupAddressExport{}, fmt.Errorf("can not parse group address export: %s", err)
+	format := export.DetectFormat(content)
+	slog.Debug("Detected group address export format", "format", format, "source", src)
+	switch format {
+	case export.FormatProject:
+		return export.ReadProject(src, options.ProjectPassword)
+	case export.FormatXML:
+		return export.ReadXML(content)
+	default:
+		return export.ReadCSV(content)
 	}
-	return addressExport, nil
 }
 
-func collectGroupAddresses(groupRange []export.GroupRange) []export.GroupAddress {
-	var addresses []export.GroupAddress
+// collectedAddress is a group address together with the names of all group ranges it is part of.
+type collectedAddress struct {
+	export.GroupAddress
+	// Ranges contains the names of the parent group ranges starting with the main group.
+	Ranges []string
+}
+
+func collectGroupAddresses(groupRange []export.GroupRange, parents ...string) []collectedAddress {
+	var addresses []collectedAddress
 
 	for _, gr := range groupRange {
-		addresses = append(addresses, gr.GroupAddress...)
-		addresses = append(addresses, collectGroupAddresses(gr.GroupRange)...)
+		ranges := append(slices.Clone(parents), gr.Name)
+		for _, ga := range gr.GroupAddress {
+			addresses = append(addresses, collectedAddress{GroupAddress: ga, Ranges: ranges})
+		}
+		addresses = append(addresses, collectGroupAddresses(gr.GroupRange, ranges...)...)
 	}
 
 	return addresses
 }
 
-func convertAddresses(groupAddresses []export.GroupAddress) map[GroupAddress]*GroupAddressConfig {
+func convertAddresses(groupAddresses []collectedAddress, rules *ConvertRules) map[GroupAddress]*GroupAddressConfig {
 	addressConfigs := make(map[GroupAddress]*GroupAddressConfig)
 	for _, ga := range groupAddresses {
 		logger := slog.With("address", ga.Address)
@@ -109,18 +201,58 @@ func convertAddresses(groupAddresses []export.GroupAddress) map[GroupAddress]*Gr
 		}
 		cfg := &GroupAddressConfig{
 			Name:       name,
-			Comment:    ga.Name + "\n" + ga.Description,
+			Comment:    ga.Name + "\n" + ga.Description + describeDevices(ga.Devices),
 			DPT:        dpt,
 			MetricType: "",
 			Export:     false,
 			ReadActive: false,
 			MaxAge:     0,
 		}
+		if rules != nil {
+			rules.apply(ga, cfg)
+		}
 		addressConfigs[address] = cfg
 	}
 	return addressConfigs
 }
 
+// convertedRangeLabels returns a function which returns the labels of the group ranges of a converted group address.
+// It uses the label names of the rules or floor and room by default. If the group ranges have no names, it falls back
+// to the main and middle group of the address.
+func convertedRangeLabels(groupAddresses []collectedAddress, rules *ConvertRules) func(GroupAddress) map[string]string {
+	labelNames := defaultRangeLabels
+	if rules != nil && len(rules.RangeLabels) > 0 {
+		labelNames = rules.RangeLabels
+	}
+	ranges := make(map[string][]string)
+	for _, ga := range groupAddresses {
+		ranges[ga.Address] = ga.Ranges
+	}
+	return func(address GroupAddress) map[string]string {
+		labels := rangeLabels(labelNames, ranges[address.String()])
+		if len(labels) == 0 {
+			return addressRangeLabel(address)
+		}
+		return labels
+	}
+}
+
+// describeDevices returns a short description of all linked devices which can be appended to the comment.
+func describeDevices(devices []export.Device) string {
+	if len(devices) == 0 {
+		return ""
+	}
+	var descriptions []string
+	for _, d := range devices {
+		description := strings.TrimSpace(d.Address + " " + d.Name)
+		if d.Channel != "" {
+			description += " (" + d.Channel + ")"
+		}
+		descriptions = append(descriptions, description)
+	}
+	return "\nDevices: " + strings.Join(descriptions, ", ")
+}
+
 var validMetricRegex = regexp.MustCompilePOSIX("^[a-zA-Z_:][a-zA-Z0-9_:]*$")
 var replaceMetricRegex = regexp.MustCompilePOSIX("[^a-zA-Z0-9_:]")
 var latin1Replacer = strings.NewReplacer("Ä", "Ae", "Ü", "Ue", "Ö", "Oe", "ä", "ae", "ü", "ue", "ö", "oe", "ß", "ss")
@@ -30,7 +30,13 @@ func TestConvertGroupAddresses(t *testing.T) {
 		wantErr    bool
 	}{
 		{"full", "fixtures/ga-export.xml", "fixtures/ga-config.yaml", false},
+		{"project", "export/fixtures/project.knxproj", "fixtures/knxproj-config.yaml", false},
+		{"ets 6 xml", "export/fixtures/ga-export-ets6.xml", "fixtures/ga-config-ets6.yaml", false},
+		{"csv 1/1", "export/fixtures/ga-export-1-1.csv", "fixtures/ga-config-ets6.yaml", false},
+		{"csv 3/1", "export/fixtures/ga-export-3-1.csv", "fixtures/ga-config-ets6.yaml", false},
+		{"csv 3/3", "export/fixtures/ga-export-3-3.csv", "fixtures/ga-config-ets6.yaml", false},
 		{"source do not exists", "fixtures/invalid.xml", "", true},
+		{"project do not exists", "fixtures/invalid.knxproj", "", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -40,7 +46,7 @@ func TestConvertGroupAddresses(t *testing.T) {
 				_ = os.Remove(tmpFile.Name())
 			}()
 
-			if err := ConvertGroupAddresses(tt.src, tmpFile.Name()); (err != nil) != tt.wantErr {
+			if _, err := ConvertGroupAddresses(tt.src, tmpFile.Name(), ConvertOptions{}); (err != nil) != tt.wantErr {
 				t.Errorf("ConvertGroupAddresses() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -0,0 +1,279 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ReadCSV parses the CSV group address export of the ETS. It supports the three variants 1/1 (name and address in
+// one column each), 3/1 (name split up into three columns by level) and 3/3 (name and address split up into three
+// columns by level). The delimiter is detected automatically.
+func ReadCSV(content []byte) (GroupAddressExport, error) {
+	content = toUTF8(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")))
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = detectDelimiter(content)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return GroupAddressExport{}, fmt.Errorf("can not parse csv group address export: %s", err)
+	}
+	if len(records) == 0 {
+		return GroupAddressExport{}, fmt.Errorf("csv group address export is empty")
+	}
+
+	columns, err := newCsvColumns(records[0])
+	if err != nil {
+		return GroupAddressExport{}, err
+	}
+
+	builder := newRangeBuilder()
+	for i, record := range records[1:] {
+		name, address := columns.nameAndAddress(record)
+		if address == "" {
+			continue
+		}
+		if err = builder.add(name, address, GroupAddress{
+			Name:        name,
+			Address:     address,
+			Central:     parseCsvBool(columns.get(record, "central")),
+			Unfiltered:  parseCsvBool(columns.get(record, "unfiltered")),
+			DPTs:        columns.get(record, "datapointtype"),
+			Description: columns.get(record, "description"),
+		}); err != nil {
+			return GroupAddressExport{}, fmt.Errorf("invalid address in line %d: %s", i+2, err)
+		}
+	}
+	return GroupAddressExport{GroupRange: builder.build()}, nil
+}
+
+// toUTF8 converts ISO 8859-1 encoded content into UTF-8 as the ETS exports CSV files in both encodings.
+func toUTF8(content []byte) []byte {
+	if utf8.Valid(content) {
+		return content
+	}
+	runes := make([]rune, len(content))
+	for i, b := range content {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
+
+func detectDelimiter(content []byte) rune {
+	header, _, _ := bytes.Cut(content, []byte("\n"))
+	delimiter := ','
+	count := 0
+	for _, d := range []rune{';', '\t', ','} {
+		if c := bytes.Count(header, []byte(string(d))); c > count {
+			delimiter = d
+			count = c
+		}
+	}
+	return delimiter
+}
+
+func parseCsvBool(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "false", "0", "no":
+		return false
+	default:
+		return true
+	}
+}
+
+type csvColumns struct {
+	// names contains the column indices of the main, middle and sub name columns or only one index for variant 1/1.
+	names []int
+	// addresses contains the column indices of the main, middle and sub address columns or only one index for the
+	// variants 1/1 and 3/1.
+	addresses []int
+	others    map[string]int
+}
+
+func newCsvColumns(header []string) (csvColumns, error) {
+	columns := csvColumns{others: make(map[string]int)}
+	for i, h := range header {
+		switch strings.ToLower(strings.TrimSpace(h)) {
+		case "group name", "name":
+			columns.names = append(columns.names, i)
+		case "main", "middle", "sub":
+			if len(columns.names) < 3 {
+				columns.names = append(columns.names, i)
+			} else {
+				columns.addresses = append(columns.addresses, i)
+			}
+		case "address":
+			columns.addresses = append(columns.addresses, i)
+		default:
+			columns.others[strings.ToLower(strings.TrimSpace(h))] = i
+		}
+	}
+
+	if (len(columns.names) != 1 && len(columns.names) != 3) || (len(columns.addresses) != 1 && len(columns.addresses) != 3) {
+		return csvColumns{}, fmt.Errorf("unknown csv group address export format with columns %s", strings.Join(header, ", "))
+	}
+	return columns, nil
+}
+
+func (c csvColumns) get(record []string, column string) string {
+	i, ok := c.others[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func (c csvColumns) nameAndAddress(record []string) (string, string) {
+	var name string
+	for _, i := range c.names {
+		if i < len(record) && strings.TrimSpace(record[i]) != "" {
+			name = strings.TrimSpace(record[i])
+			break
+		}
+	}
+
+	if len(c.addresses) == 1 {
+		if c.addresses[0] >= len(record) {
+			return name, ""
+		}
+		return name, strings.TrimSpace(record[c.addresses[0]])
+	}
+
+	var parts []string
+	for _, i := range c.addresses {
+		part := ""
+		if i < len(record) {
+			part = strings.TrimSpace(record[i])
+		}
+		if part == "" {
+			part = "-"
+		}
+		parts = append(parts, part)
+	}
+	if parts[0] == "-" {
+		return name, ""
+	}
+	return name, strings.Join(parts, "/")
+}
+
+// rangeBuilder builds the group range hierarchy from the flat list of ranges and group addresses.
+type rangeBuilder struct {
+	roots  []*rangeNode
+	ranges map[string]*rangeNode
+}
+
+type rangeNode struct {
+	groupRange GroupRange
+	children   []*rangeNode
+}
+
+func newRangeBuilder() *rangeBuilder {
+	return &rangeBuilder{ranges: make(map[string]*rangeNode)}
+}
+
+// add adds either a new group range for addresses like "1/-/-" and "1/2/-" or a group address to the range it
+// belongs to.
+func (b *rangeBuilder) add(name string, address string, ga GroupAddress) error {
+	parts := strings.Split(address, "/")
+	depth := len(parts)
+	for i, p := range parts {
+		if p == "-" {
+			depth = i
+			break
+		}
+	}
+	if depth == 0 {
+		return fmt.Errorf("\"%s\" is not a valid group address", address)
+	}
+
+	if depth == len(parts) {
+		parent := b.findParent(parts[:depth-1])
+		parent.groupRange.GroupAddress = append(parent.groupRange.GroupAddress, ga)
+		return nil
+	}
+
+	start, end, err := rangeLimits(parts[:depth])
+	if err != nil {
+		return err
+	}
+	node := &rangeNode{groupRange: GroupRange{Name: name, RangeStart: start, RangeEnd: end}}
+	b.ranges[strings.Join(parts[:depth], "/")] = node
+	if depth == 1 {
+		b.roots = append(b.roots, node)
+	} else {
+		parent := b.findParent(parts[:depth-1])
+		parent.children = append(parent.children, node)
+	}
+	return nil
+}
+
+// findParent returns the deepest existing range for the given address prefix. Missing main ranges are created
+// without a name.
+func (b *rangeBuilder) findParent(prefix []string) *rangeNode {
+	for i := len(prefix); i > 0; i-- {
+		if node, ok := b.ranges[strings.Join(prefix[:i], "/")]; ok {
+			return node
+		}
+	}
+	key := ""
+	if len(prefix) > 0 {
+		key = prefix[0]
+	}
+	node, ok := b.ranges[key]
+	if !ok {
+		node = &rangeNode{}
+		b.ranges[key] = node
+		b.roots = append(b.roots, node)
+	}
+	return node
+}
+
+func rangeLimits(parts []string) (uint16, uint16, error) {
+	main, err := strconv.ParseUint(parts[0], 10, 5)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(parts) == 1 {
+		return uint16(main << 11), uint16(main<<11 | 0x7ff), nil
+	}
+	middle, err := strconv.ParseUint(parts[1], 10, 3)
+	if err != nil {
+		return 0, 0, err
+	}
+	start := uint16(main<<11 | middle<<8)
+	return start, start | 0xff, nil
+}
+
+func (b *rangeBuilder) build() []GroupRange {
+	var ranges []GroupRange
+	for _, node := range b.roots {
+		ranges = append(ranges, node.build())
+	}
+	return ranges
+}
+
+func (n *rangeNode) build() GroupRange {
+	gr := n.groupRange
+	for _, child := range n.children {
+		gr.GroupRange = append(gr.GroupRange, child.build())
+	}
+	return gr
+}
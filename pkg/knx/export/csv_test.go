@@ -0,0 +1,72 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCSV(t *testing.T) {
+	want := GroupAddressExport{GroupRange: []GroupRange{
+		{Name: "Ground floor", RangeStart: 0, RangeEnd: 2047, GroupRange: []GroupRange{
+			{Name: "Kitchen", RangeStart: 0, RangeEnd: 255, GroupAddress: []GroupAddress{
+				{Name: "Temperatur Küche", Address: "0/0/1", DPTs: "DPST-9-1", Description: "Room temperature"},
+				{Name: "Light", Address: "0/0/2", Central: true, DPTs: "DPST-1-1"},
+			}},
+		}},
+		{Name: "First floor", RangeStart: 2048, RangeEnd: 4095, GroupRange: []GroupRange{
+			{Name: "Bath", RangeStart: 2048, RangeEnd: 2303, GroupAddress: []GroupAddress{
+				{Name: "Light status", Address: "1/0/1", Unfiltered: true, DPTs: "DPT-1"},
+			}},
+		}},
+	}}
+	tests := []struct {
+		name    string
+		src     string
+		want    GroupAddressExport
+		wantErr bool
+	}{
+		{"1/1", "fixtures/ga-export-1-1.csv", want, false},
+		{"3/1", "fixtures/ga-export-3-1.csv", want, false},
+		{"3/3", "fixtures/ga-export-3-3.csv", want, false},
+		{"unknown columns", "fixtures/ga-export-ets6.xml", GroupAddressExport{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile(tt.src)
+			assert.NoError(t, err)
+
+			got, err := ReadCSV(content)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadCSV() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadCSV_WithoutRanges(t *testing.T) {
+	content := []byte("Group name\tAddress\tDatapointType\nLight\t1/2/3\tDPST-1-1\nOther\t12\t\n")
+	got, err := ReadCSV(content)
+	assert.NoError(t, err)
+	assert.Equal(t, GroupAddressExport{GroupRange: []GroupRange{
+		{GroupAddress: []GroupAddress{{Name: "Light", Address: "1/2/3", DPTs: "DPST-1-1"}}},
+		{GroupAddress: []GroupAddress{{Name: "Other", Address: "12"}}},
+	}}, got)
+}
@@ -0,0 +1,280 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/vapourismo/knx-go/knx/cemi"
+)
+
+// projectDataFile is the name of the file within an ETS project which contains the actual project data.
+const projectDataFile = "0.xml"
+
+// etsSixPasswordSalt is the salt which ETS 6 uses to derive the actual archive password from the project password.
+const etsSixPasswordSalt = "21.project.ets.knx.org"
+
+var projectDataRegex = regexp.MustCompile(`^P-[0-9A-F]{4}/0\.xml$`)
+var protectedProjectRegex = regexp.MustCompile(`^P-[0-9A-F]{4}\.zip$`)
+
+// ReadProject reads all group addresses from the given ETS project file (.knxproj). It returns them in the same
+// structure as the XML group address export but additionally contains all devices linked to the group addresses.
+// The password is only required if the project is password protected.
+func ReadProject(src string, password string) (GroupAddressExport, error) {
+	archive, err := zip.OpenReader(src)
+	if err != nil {
+		return GroupAddressExport{}, fmt.Errorf("can not open project file '%s': %s", src, err)
+	}
+	defer func() { _ = archive.Close() }()
+
+	data, err := readProjectData(&archive.Reader, password)
+	if err != nil {
+		return GroupAddressExport{}, fmt.Errorf("can not read project file '%s': %s", src, err)
+	}
+
+	project := projectFile{}
+	if err = xml.Unmarshal(data, &project); err != nil {
+		return GroupAddressExport{}, fmt.Errorf("can not parse project file '%s': %s", src, err)
+	}
+	return project.toGroupAddressExport(), nil
+}
+
+// readProjectData finds and reads the project data either directly from the project archive or from the password
+// protected inner archive.
+func readProjectData(archive *zip.Reader, password string) ([]byte, error) {
+	for _, f := range archive.File {
+		if projectDataRegex.MatchString(f.Name) {
+			return readAll(f.Open())
+		}
+	}
+
+	for _, f := range archive.File {
+		if !protectedProjectRegex.MatchString(f.Name) {
+			continue
+		}
+		if password == "" {
+			return nil, fmt.Errorf("project is password protected but no password was given")
+		}
+		content, err := readAll(f.Open())
+		if err != nil {
+			return nil, err
+		}
+		inner, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		if err != nil {
+			return nil, fmt.Errorf("can not open protected project %s: %s", f.Name, err)
+		}
+		return readProtectedProjectData(inner, password)
+	}
+	return nil, fmt.Errorf("no project data found")
+}
+
+// readProtectedProjectData reads the project data from the inner archive of a password protected project. ETS 5 uses
+// the project password directly while ETS 6 derives the archive password from it. Both variants are tried.
+func readProtectedProjectData(archive *zip.Reader, password string) ([]byte, error) {
+	for _, f := range archive.File {
+		if path.Base(f.Name) != projectDataFile {
+			continue
+		}
+		data, err := readEncrypted(f, password)
+		if err == nil {
+			return data, nil
+		}
+		data, etsSixErr := readEncrypted(f, deriveEtsSixPassword(password))
+		if etsSixErr == nil {
+			return data, nil
+		}
+		return nil, err
+	}
+	return nil, fmt.Errorf("no project data found in protected project")
+}
+
+func deriveEtsSixPassword(password string) string {
+	encoded := utf16.Encode([]rune(password))
+	utf16le := make([]byte, 0, len(encoded)*2)
+	for _, c := range encoded {
+		utf16le = append(utf16le, byte(c), byte(c>>8))
+	}
+	key, err := pbkdf2.Key(sha256.New, string(utf16le), []byte(etsSixPasswordSalt), 65536, 32)
+	if err != nil {
+		return password
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// Device defines a single device that is linked to a group address within an ETS project.
+type Device struct {
+	// Address is the physical address of the device. It is empty if the device is not assigned to a line.
+	Address string
+	// Name is the name of the device.
+	Name string
+	// Channel contains the text of the communication object that links the device with the group address.
+	Channel string
+}
+
+type projectFile struct {
+	XMLName       xml.Name              `xml:"KNX"`
+	Installations []projectInstallation `xml:"Project>Installations>Installation"`
+}
+
+type projectInstallation struct {
+	Areas             []projectArea       `xml:"Topology>Area"`
+	UnassignedDevices []projectDevice     `xml:"Topology>UnassignedDevices>DeviceInstance"`
+	GroupRanges       []projectGroupRange `xml:"GroupAddresses>GroupRanges>GroupRange"`
+}
+
+type projectArea struct {
+	Address uint8         `xml:"Address,attr"`
+	Lines   []projectLine `xml:"Line"`
+}
+
+type projectLine struct {
+	Address  uint8           `xml:"Address,attr"`
+	Devices  []projectDevice `xml:"DeviceInstance"`
+	Segments []struct {
+		Devices []projectDevice `xml:"DeviceInstance"`
+	} `xml:"Segment"`
+}
+
+type projectDevice struct {
+	Name       string             `xml:"Name,attr"`
+	Address    string             `xml:"Address,attr"`
+	ComObjects []projectComObject `xml:"ComObjectInstanceRefs>ComObjectInstanceRef"`
+}
+
+type projectComObject struct {
+	Text         string `xml:"Text,attr"`
+	FunctionText string `xml:"FunctionText,attr"`
+	// Links is used by ETS 6 to reference the group addresses.
+	Links string `xml:"Links,attr"`
+	// Send and Receive are used by ETS 5 to reference the group addresses.
+	Send    []projectConnector `xml:"Connectors>Send"`
+	Receive []projectConnector `xml:"Connectors>Receive"`
+}
+
+type projectConnector struct {
+	GroupAddressRefId string `xml:"GroupAddressRefId,attr"`
+}
+
+type projectGroupRange struct {
+	Name           string                `xml:"Name,attr"`
+	RangeStart     uint16                `xml:"RangeStart,attr"`
+	RangeEnd       uint16                `xml:"RangeEnd,attr"`
+	GroupRanges    []projectGroupRange   `xml:"GroupRange"`
+	GroupAddresses []projectGroupAddress `xml:"GroupAddress"`
+}
+
+type projectGroupAddress struct {
+	Id            string `xml:"Id,attr"`
+	Name          string `xml:"Name,attr"`
+	Address       uint16 `xml:"Address,attr"`
+	Central       bool   `xml:"Central,attr"`
+	Unfiltered    bool   `xml:"Unfiltered,attr"`
+	DatapointType string `xml:"DatapointType,attr"`
+	Description   string `xml:"Description,attr"`
+}
+
+func (p projectFile) toGroupAddressExport() GroupAddressExport {
+	addressExport := GroupAddressExport{}
+	for _, installation := range p.Installations {
+		devices := installation.linkedDevices()
+		for _, gr := range installation.GroupRanges {
+			addressExport.GroupRange = append(addressExport.GroupRange, gr.toGroupRange(devices))
+		}
+	}
+	return addressExport
+}
+
+// linkedDevices collects all devices of the installation by the local id of the group addresses they are linked to.
+func (i projectInstallation) linkedDevices() map[string][]Device {
+	devices := make(map[string][]Device)
+	addDevice := func(address string, device projectDevice) {
+		for _, co := range device.ComObjects {
+			channel := co.Text
+			if co.FunctionText != "" {
+				channel = strings.TrimSpace(channel + " " + co.FunctionText)
+			}
+			d := Device{Address: address, Name: device.Name, Channel: channel}
+			for _, id := range co.groupAddressIds() {
+				devices[localId(id)] = append(devices[localId(id)], d)
+			}
+		}
+	}
+
+	for _, area := range i.Areas {
+		for _, line := range area.Lines {
+			lineDevices := line.Devices
+			for _, segment := range line.Segments {
+				lineDevices = append(lineDevices, segment.Devices...)
+			}
+			for _, device := range lineDevices {
+				address := ""
+				if device.Address != "" {
+					address = fmt.Sprintf("%d.%d.%s", area.Address, line.Address, device.Address)
+				}
+				addDevice(address, device)
+			}
+		}
+	}
+	for _, device := range i.UnassignedDevices {
+		addDevice("", device)
+	}
+	return devices
+}
+
+func (c projectComObject) groupAddressIds() []string {
+	ids := strings.Fields(c.Links)
+	for _, connector := range append(c.Send, c.Receive...) {
+		ids = append(ids, connector.GroupAddressRefId)
+	}
+	return ids
+}
+
+func (r projectGroupRange) toGroupRange(devices map[string][]Device) GroupRange {
+	gr := GroupRange{
+		Name:       r.Name,
+		RangeStart: r.RangeStart,
+		RangeEnd:   r.RangeEnd,
+	}
+	for _, child := range r.GroupRanges {
+		gr.GroupRange = append(gr.GroupRange, child.toGroupRange(devices))
+	}
+	for _, ga := range r.GroupAddresses {
+		gr.GroupAddress = append(gr.GroupAddress, GroupAddress{
+			Name:        ga.Name,
+			Address:     cemi.GroupAddr(ga.Address).String(),
+			Central:     ga.Central,
+			Unfiltered:  ga.Unfiltered,
+			DPTs:        ga.DatapointType,
+			Description: ga.Description,
+			Devices:     devices[localId(ga.Id)],
+		})
+	}
+	return gr
+}
+
+// localId removes the project specific prefix from ids like "P-0123-0_GA-1".
+func localId(id string) string {
+	return id[strings.LastIndex(id, "_")+1:]
+}
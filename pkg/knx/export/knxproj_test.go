@@ -0,0 +1,103 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadProject(t *testing.T) {
+	ets6Devices := map[string][]Device{
+		"0/0/1": {{Address: "1.1.5", Name: "Temperature sensor", Channel: "Channel A Temperature"}},
+		"0/0/2": {{Address: "1.1.6", Name: "Switch actuator", Channel: "Output A"}, {Name: "Push button", Channel: "Button 1"}},
+		"1/0/1": {{Address: "1.1.6", Name: "Switch actuator", Channel: "Output A"}},
+	}
+	tests := []struct {
+		name     string
+		src      string
+		password string
+		wantErr  bool
+	}{
+		{"unprotected", "fixtures/project.knxproj", "", false},
+		{"ets 6 protected", "fixtures/project-ets6-protected.knxproj", "secret", false},
+		{"ets 5 protected", "fixtures/project-ets5-protected.knxproj", "secret", false},
+		{"missing password", "fixtures/project-ets6-protected.knxproj", "", true},
+		{"wrong ets 6 password", "fixtures/project-ets6-protected.knxproj", "wrong", true},
+		{"wrong ets 5 password", "fixtures/project-ets5-protected.knxproj", "wrong", true},
+		{"not existing", "fixtures/invalid.knxproj", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadProject(tt.src, tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadProject() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			assert.Len(t, got.GroupRange, 2)
+			assert.Equal(t, "Ground floor", got.GroupRange[0].Name)
+			assert.Equal(t, "Kitchen", got.GroupRange[0].GroupRange[0].Name)
+			assert.Equal(t, "First floor", got.GroupRange[1].Name)
+			assert.Equal(t, "Bath", got.GroupRange[1].GroupRange[0].Name)
+
+			kitchen := got.GroupRange[0].GroupRange[0].GroupAddress
+			assert.Len(t, kitchen, 2)
+			assert.Equal(t, GroupAddress{
+				Name:        "Temperature",
+				Address:     "0/0/1",
+				DPTs:        "DPST-9-1",
+				Description: "Room temperature",
+				Devices:     ets6Devices["0/0/1"],
+			}, kitchen[0])
+			assert.Equal(t, GroupAddress{
+				Name:    "Light",
+				Address: "0/0/2",
+				Central: true,
+				DPTs:    "DPST-1-1",
+				Devices: ets6Devices["0/0/2"],
+			}, kitchen[1])
+
+			bath := got.GroupRange[1].GroupRange[0].GroupAddress
+			assert.Equal(t, GroupAddress{
+				Name:       "Light status",
+				Address:    "1/0/1",
+				Unfiltered: true,
+				DPTs:       "DPT-1",
+				Devices:    ets6Devices["1/0/1"],
+			}, bath[0])
+		})
+	}
+}
+
+func Test_localId(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"P-0123-0_GA-1", "GA-1"},
+		{"GA-1", "GA-1"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			assert.Equal(t, tt.want, localId(tt.id))
+		})
+	}
+}
@@ -18,7 +18,7 @@ import (
 	"encoding/xml"
 )
 
-// GroupAddressExport is the root element of the xml file which generates the ETS 5 application
+// GroupAddressExport is the root element of the xml file which generates the ETS 5 and ETS 6 application
 // while the group address export.
 type GroupAddressExport struct {
 	XMLName    xml.Name     `xml:"GroupAddress-Export"`
@@ -35,7 +35,7 @@ type GroupRange struct {
 	GroupAddress []GroupAddress `xml:"GroupAddress"`
 }
 
-// GroupAddress defines a a single group address in the ETS 5 group address export.
+// GroupAddress defines a a single group address in the ETS 5 and ETS 6 group address export.
 type GroupAddress struct {
 	Name        string `xml:"Name,attr"`
 	Address     string `xml:"Address,attr"`
@@ -43,4 +43,25 @@ type GroupAddress struct {
 	Unfiltered  bool   `xml:"Unfiltered,attr"`
 	DPTs        string `xml:"DPTs,attr"`
 	Description string `xml:"Description,attr"`
+	// Devices contains all devices that are linked to the group address. It is only available if the group
+	// addresses were read from an ETS project file.
+	Devices []Device `xml:"-"`
+}
+
+// UnmarshalXML parses a single group address. It also accepts the DatapointType attribute which is used by the ETS 6
+// instead of the DPTs attribute.
+func (g *GroupAddress) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plainGroupAddress GroupAddress
+	var ga struct {
+		plainGroupAddress
+		DatapointType string `xml:"DatapointType,attr"`
+	}
+	if err := d.DecodeElement(&ga, &start); err != nil {
+		return err
+	}
+	*g = GroupAddress(ga.plainGroupAddress)
+	if g.DPTs == "" {
+		g.DPTs = ga.DatapointType
+	}
+	return nil
 }
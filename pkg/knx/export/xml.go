@@ -0,0 +1,54 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+// Format defines the file format of a group address export.
+type Format string
+
+// FormatXML is the XML group address export of the ETS 5 and ETS 6.
+const FormatXML = Format("xml")
+
+// FormatCSV is the CSV group address export of the ETS in one of the variants 1/1, 3/1 or 3/3.
+const FormatCSV = Format("csv")
+
+// FormatProject is the ETS project file (.knxproj).
+const FormatProject = Format("knxproj")
+
+// DetectFormat detects the format of the given group address export by its content.
+func DetectFormat(content []byte) Format {
+	if bytes.HasPrefix(content, []byte("PK\x03\x04")) {
+		return FormatProject
+	}
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")))
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		return FormatXML
+	}
+	return FormatCSV
+}
+
+// ReadXML parses the XML group address export of the ETS 5 or ETS 6.
+func ReadXML(content []byte) (GroupAddressExport, error) {
+	addressExport := GroupAddressExport{}
+	if err := xml.Unmarshal(content, &addressExport); err != nil {
+		return GroupAddressExport{}, fmt.Errorf("can not parse group address export: %s", err)
+	}
+	return addressExport, nil
+}
@@ -0,0 +1,50 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want Format
+	}{
+		{"ets 6 xml", "fixtures/ga-export-ets6.xml", FormatXML},
+		{"csv", "fixtures/ga-export-1-1.csv", FormatCSV},
+		{"project", "fixtures/project.knxproj", FormatProject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile(tt.src)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, DetectFormat(content))
+		})
+	}
+}
+
+func TestReadXML(t *testing.T) {
+	content, err := os.ReadFile("fixtures/ga-export-ets6.xml")
+	assert.NoError(t, err)
+
+	got, err := ReadXML(content)
+	assert.NoError(t, err)
+	assert.Equal(t, GroupAddress{Name: "Light", Address: "0/0/2", Central: true, DPTs: "DPST-1-1"}, got.GroupRange[0].GroupRange[0].GroupAddress[1])
+}
@@ -0,0 +1,203 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// zipMethodAES is the compression method which marks a WinZip AES encrypted entry.
+const zipMethodAES = 99
+
+// zipExtraAES is the id of the extra field which contains the WinZip AES encryption parameters.
+const zipExtraAES = 0x9901
+
+// readEncrypted reads and decrypts the given zip file entry using the password. It supports the traditional PKWARE
+// encryption and the WinZip AES encryption. Entries which are not encrypted will be read as is.
+func readEncrypted(f *zip.File, password string) ([]byte, error) {
+	if f.Flags&0x1 == 0 {
+		return readAll(f.Open())
+	}
+
+	raw, err := readAll(f.OpenRaw())
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	method := f.Method
+	if method == zipMethodAES {
+		data, method, err = decryptAES(f, raw, password)
+	} else {
+		data, err = decryptZipCrypto(f, raw, password)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch method {
+	case zip.Store:
+	case zip.Deflate:
+		data, err = io.ReadAll(flate.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			return nil, fmt.Errorf("can not decompress %s: %s", f.Name, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression method %d for %s", method, f.Name)
+	}
+
+	if f.CRC32 != 0 && crc32.ChecksumIEEE(data) != f.CRC32 {
+		return nil, fmt.Errorf("checksum mismatch for %s: wrong password", f.Name)
+	}
+	return data, nil
+}
+
+func readAll(reader io.Reader, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
+	return io.ReadAll(reader)
+}
+
+// decryptAES decrypts the data of a WinZip AES encrypted entry. It returns the decrypted data together with the
+// actual compression method.
+func decryptAES(f *zip.File, raw []byte, password string) ([]byte, uint16, error) {
+	strength, method, err := parseAESExtra(f.Extra)
+	if err != nil {
+		return nil, 0, fmt.Errorf("can not decrypt %s: %s", f.Name, err)
+	}
+
+	keyLength := 8 + 8*int(strength)
+	saltLength := keyLength / 2
+	if len(raw) < saltLength+2+10 {
+		return nil, 0, fmt.Errorf("encrypted data of %s is too short", f.Name)
+	}
+	salt := raw[:saltLength]
+	verifier := raw[saltLength : saltLength+2]
+	encrypted := raw[saltLength+2 : len(raw)-10]
+	authCode := raw[len(raw)-10:]
+
+	keys, err := pbkdf2.Key(sha1.New, password, salt, 1000, 2*keyLength+2)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !bytes.Equal(keys[2*keyLength:], verifier) {
+		return nil, 0, fmt.Errorf("can not decrypt %s: wrong password", f.Name)
+	}
+
+	mac := hmac.New(sha1.New, keys[keyLength:2*keyLength])
+	mac.Write(encrypted)
+	if !hmac.Equal(mac.Sum(nil)[:10], authCode) {
+		return nil, 0, fmt.Errorf("can not decrypt %s: authentication failed", f.Name)
+	}
+
+	block, err := aes.NewCipher(keys[:keyLength])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// WinZip AES uses AES-CTR with a little endian counter starting at 1.
+	data := make([]byte, len(encrypted))
+	counter := make([]byte, aes.BlockSize)
+	stream := make([]byte, aes.BlockSize)
+	for i := 0; i < len(encrypted); i += aes.BlockSize {
+		binary.LittleEndian.PutUint64(counter, uint64(i/aes.BlockSize+1))
+		block.Encrypt(stream, counter)
+		for j := i; j < len(encrypted) && j < i+aes.BlockSize; j++ {
+			data[j] = encrypted[j] ^ stream[j-i]
+		}
+	}
+	return data, method, nil
+}
+
+// parseAESExtra extracts the key strength and the actual compression method from the WinZip AES extra field.
+func parseAESExtra(extra []byte) (byte, uint16, error) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra)
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		extra = extra[4:]
+		if size > len(extra) {
+			break
+		}
+		if id == zipExtraAES && size >= 7 {
+			strength := extra[4]
+			if strength < 1 || strength > 3 {
+				return 0, 0, fmt.Errorf("invalid aes strength %d", strength)
+			}
+			return strength, binary.LittleEndian.Uint16(extra[5:]), nil
+		}
+		extra = extra[size:]
+	}
+	return 0, 0, fmt.Errorf("missing aes extra field")
+}
+
+// decryptZipCrypto decrypts the data of an entry which uses the traditional PKWARE encryption.
+func decryptZipCrypto(f *zip.File, raw []byte, password string) ([]byte, error) {
+	if len(raw) < 12 {
+		return nil, fmt.Errorf("encrypted data of %s is too short", f.Name)
+	}
+	keys := newZipCryptoKeys(password)
+	data := make([]byte, len(raw))
+	for i, c := range raw {
+		data[i] = keys.decrypt(c)
+	}
+
+	// The last byte of the encryption header contains either the high byte of the crc or of the modification
+	// time if the crc is stored within the data descriptor.
+	check := byte(f.CRC32 >> 24)
+	if f.Flags&0x8 != 0 {
+		check = byte(f.ModifiedTime >> 8)
+	}
+	if data[11] != check {
+		return nil, fmt.Errorf("can not decrypt %s: wrong password", f.Name)
+	}
+	return data[12:], nil
+}
+
+type zipCryptoKeys [3]uint32
+
+func newZipCryptoKeys(password string) *zipCryptoKeys {
+	keys := &zipCryptoKeys{305419896, 591751049, 878082192}
+	for i := 0; i < len(password); i++ {
+		keys.update(password[i])
+	}
+	return keys
+}
+
+func (k *zipCryptoKeys) update(c byte) {
+	k[0] = crc32.IEEETable[byte(k[0])^c] ^ (k[0] >> 8)
+	k[1] = (k[1]+(k[0]&0xff))*134775813 + 1
+	k[2] = crc32.IEEETable[byte(k[2])^byte(k[1]>>24)] ^ (k[2] >> 8)
+}
+
+func (k *zipCryptoKeys) decrypt(c byte) byte {
+	temp := uint16(k[2]) | 2
+	plain := c ^ byte((uint32(temp)*uint32(temp^1))>>8)
+	k.update(plain)
+	return plain
+}
@@ -20,80 +20,410 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/vapourismo/knx-go/knx"
+	"github.com/vapourismo/knx-go/knx/cemi"
 )
 
 type MetricsExporter interface {
 	Run(ctx context.Context) error
+	// IsAlive returns an error describing all components which are down. Failed read requests of the poller are not
+	// part of it as they are recovered by reconnecting.
 	IsAlive() error
+	// Health returns the health of the connection, the listener, the poller, the snapshot handler and all outputs.
+	Health() []ComponentHealth
+	// Reconnect closes the connection to the KNX gateway and connects again. The listener and the poller are restarted
+	// using the new connection.
+	Reconnect(ctx context.Context) error
+	// Installation returns the name of the exported KNX installation. It is empty if the installation has no name.
+	Installation() string
+	// Config returns the effective configuration of the exported KNX installation.
+	Config() *Config
+	// Status returns the connection state and the latest values of all configured group addresses.
+	Status() ExporterStatus
+	// Subscribe sends all received telegrams matching the filter to the given channel until the returned function is
+	// called. Telegrams are dropped if the channel is full.
+	Subscribe(telegrams chan<- TelegramEvent, filter TelegramFilter) func()
+	// Write encodes the human-readable value using the dpt of the configured group address and sends it as GroupWrite
+	// telegram.
+	Write(address GroupAddress, value string) error
+	// IsConnected returns an error if the exporter is not connected to the KNX gateway.
+	IsConnected() error
+	// IsStartupReadFinished returns an error while the read requests after startup are sent and the timeout since
+	// starting the exporter is not exceeded.
+	IsStartupReadFinished(timeout time.Duration) error
+	// HasFreshValues returns an error if less than minPercent of the actively read group addresses have a value
+	// which is younger than their MaxAge. Group addresses outside their ReadWindows are ignored.
+	HasFreshValues(minPercent float64) error
 }
 
 type metricsExporter struct {
 	config *Config
+	// lock guards the client, listener, poller and health which are replaced on reconnects.
+	lock   sync.RWMutex
 	client GroupClient
+	// cancel stops the listener and the poller of the current connection.
+	cancel context.CancelFunc
+	// dial creates a new connection to the KNX gateway.
+	dial func() (GroupClient, error)
 
 	metrics        MetricSnapshotHandler
+	queue          SnapshotQueue
 	listener       Listener
 	messageCounter *prometheus.CounterVec
 	poller         Poller
+	outputs        []SnapshotOutput
+	telegrams      *telegramBroadcaster
 	health         error
+	startedAt      time.Time
+	components     *healthTracker
 }
 
-func NewMetricsExporter(configFile string, registerer prometheus.Registerer) (MetricsExporter, error) {
+// NewMetricsExporters creates a MetricsExporter for every KNX installation defined within the given configuration file.
+// All of them register their metrics at the given registerer.
+func NewMetricsExporters(configFile string, registerer prometheus.Registerer) ([]MetricsExporter, error) {
 	config, err := ReadConfig(configFile)
 	if err != nil {
 		return nil, err
 	}
+
+	var exporters []MetricsExporter
+	for _, installation := range config.GetInstallations() {
+		m, err := newMetricsExporter(installation, registerer)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, m)
+	}
+	return exporters, nil
+}
+
+func newMetricsExporter(config *Config, registerer prometheus.Registerer) (*metricsExporter, error) {
+	if config.Installation != "" {
+		registerer = prometheus.WrapRegistererWith(prometheus.Labels{"installation": config.Installation}, registerer)
+	}
+
 	m := &metricsExporter{
-		config:  config,
-		metrics: NewMetricsSnapshotHandler(),
+		config:     config,
+		metrics:    NewMetricsSnapshotHandler(config.SnapshotQueue.Size),
+		telegrams:  newTelegramBroadcaster(),
+		components: newHealthTracker(config.Installation),
 		messageCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name:      "messages",
 			Namespace: "knx",
 		}, []string{"direction", "processed"}),
 	}
-	if err = registerer.Register(m.messageCounter); err != nil {
+	if err := validateAlertActions(config); err != nil {
+		return nil, err
+	}
+	m.dial = m.createClient
+	m.queue = NewSnapshotQueue(m.metrics.GetMetricsChannel(), config.SnapshotQueue.OverflowPolicy)
+	if config.MQTT != nil && config.MQTT.Broker != "" {
+		output, err := NewMQTTOutput(config)
+		if err != nil {
+			return nil, err
+		}
+		m.outputs = append(m.outputs, output)
+	}
+	if config.InfluxDB != nil && config.InfluxDB.URL != "" {
+		output, err := NewInfluxDBOutput(config)
+		if err != nil {
+			return nil, err
+		}
+		m.outputs = append(m.outputs, output)
+	}
+	if config.OTLP != nil && config.OTLP.Endpoint != "" {
+		output, err := NewOTLPOutput(config)
+		if err != nil {
+			return nil, err
+		}
+		m.outputs = append(m.outputs, output)
+	}
+	for _, output := range m.outputs {
+		m.metrics.AddOutput(output)
+	}
+	if err := registerer.Register(m.messageCounter); err != nil {
 		return nil, fmt.Errorf("can not register message counter metrics: %s", err)
 	}
-	if err = registerer.Register(m.metrics); err != nil {
+	if err := registerer.Register(m.metrics); err != nil {
 		return nil, fmt.Errorf("can not register metrics collector: %s", err)
 	}
+	if err := registerer.Register(m.queue); err != nil {
+		return nil, fmt.Errorf("can not register snapshot queue metrics: %s", err)
+	}
+	if err := registerer.Register(&healthCollector{exporter: m}); err != nil {
+		return nil, fmt.Errorf("can not register component health metrics: %s", err)
+	}
+	for _, output := range m.outputs {
+		if collector, ok := output.(prometheus.Collector); ok {
+			if err := registerer.Register(collector); err != nil {
+				return nil, fmt.Errorf("can not register %s output metrics: %s", output.Name(), err)
+			}
+		}
+	}
 	return m, nil
 }
 
 func (e *metricsExporter) Run(ctx context.Context) error {
-	e.poller = NewPoller(e.config, e.metrics, e.messageCounter)
-	e.listener = NewListener(e.config, e.metrics.GetMetricsChannel(), e.messageCounter)
+	e.startedAt = time.Now()
 	go e.metrics.Run(ctx)
+	for _, output := range e.outputs {
+		go output.Run(ctx)
+	}
 
-	if err := e.createClient(); err != nil {
-		e.health = err
+	if err := e.connect(ctx); err != nil {
 		return err
 	}
 	context.AfterFunc(ctx, func() {
-		e.client.Close()
+		e.lock.Lock()
+		defer e.lock.Unlock()
+		e.disconnect()
 	})
+	return nil
+}
 
-	go e.listener.Run(ctx, e.client.Inbound())
-	e.poller.Run(ctx, e.client, true)
+func (e *metricsExporter) Reconnect(ctx context.Context) error {
+	e.lock.Lock()
+	e.disconnect()
+	e.lock.Unlock()
+	return e.connect(ctx)
+}
 
+// connect creates a new connection to the KNX gateway and starts a new listener and poller using it.
+func (e *metricsExporter) connect(ctx context.Context) error {
+	client, err := e.dial()
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err != nil {
+		e.health = err
+		return err
+	}
+	if ctx.Err() != nil {
+		client.Close()
+		return ctx.Err()
+	}
+	connectionCtx, cancel := context.WithCancel(ctx)
+	e.client = client
+	e.cancel = cancel
+	e.health = nil
+	e.poller = NewPoller(e.config, e.metrics, e.messageCounter)
+	e.listener = NewListener(e.config, e.queue, e.messageCounter, e.telegrams)
+
+	go e.listener.Run(connectionCtx, client.Inbound())
+	e.poller.Run(connectionCtx, client, true)
 	return nil
 }
 
+// disconnect stops the listener and the poller and closes the connection. The lock must be held by the caller.
+func (e *metricsExporter) disconnect() {
+	if e.cancel != nil {
+		e.cancel()
+		e.cancel = nil
+	}
+	if e.client != nil {
+		e.client.Close()
+		e.client = nil
+	}
+}
+
 func (e *metricsExporter) IsAlive() error {
-	if !e.listener.IsActive() {
-		return fmt.Errorf("listener is closed")
+	var failures []string
+	for _, component := range e.Health() {
+		if component.State == ComponentDown && component.Component != ComponentPoller {
+			failures = append(failures, component.Component+": "+component.LastError)
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+func (e *metricsExporter) Health() []ComponentHealth {
+	e.lock.RLock()
+	connectionErr := e.connectionError()
+	listener, poller := e.listener, e.poller
+	e.lock.RUnlock()
+
+	var listenerErr, pollerErr error
+	var lastReceived, lastSent time.Time
+	if listener == nil {
+		listenerErr = fmt.Errorf("listener is not started")
+	} else {
+		if !listener.IsActive() {
+			listenerErr = fmt.Errorf("listener is closed")
+		}
+		lastReceived = listener.LastTelegram()
+	}
+	if poller == nil {
+		pollerErr = fmt.Errorf("poller is not started")
+	} else {
+		pollerErr = poller.LastError()
+		lastSent = poller.LastTelegram()
 	}
+	var snapshotHandlerErr error
 	if !e.metrics.IsActive() {
-		return fmt.Errorf("metric snapshot handler is closed")
+		snapshotHandlerErr = fmt.Errorf("metric snapshot handler is closed")
 	}
 
-	return e.health
+	components := []ComponentHealth{
+		e.components.update(ComponentConnection, true, connectionErr, lastReceived),
+		e.components.update(ComponentListener, true, listenerErr, lastReceived),
+		e.components.update(ComponentPoller, true, pollerErr, lastSent),
+		e.components.update(ComponentSnapshotHandler, false, snapshotHandlerErr, time.Time{}),
+	}
+	for _, output := range e.outputs {
+		var outputErr error
+		if !output.IsActive() {
+			outputErr = fmt.Errorf("snapshot output is closed")
+		}
+		components = append(components, e.components.update(output.Name(), false, outputErr, time.Time{}))
+	}
+	return components
+}
+
+func (e *metricsExporter) Installation() string {
+	return e.config.Installation
+}
+
+func (e *metricsExporter) Config() *Config {
+	return e.config
 }
 
-func (e *metricsExporter) createClient() error {
+func (e *metricsExporter) Status() ExporterStatus {
+	connection := ConnectionStatus{
+		Installation: e.config.Installation,
+		Type:         e.config.Connection.Type,
+		Endpoint:     e.config.Connection.Endpoint,
+	}
+	if err := e.IsConnected(); err != nil {
+		connection.Error = err.Error()
+	} else {
+		connection.Connected = true
+	}
+
+	snapshots := make(map[GroupAddress][]*Snapshot)
+	for _, s := range e.metrics.Snapshots() {
+		snapshots[s.destination] = append(snapshots[s.destination], s)
+	}
+	addresses := slices.Sorted(maps.Keys(e.config.AddressConfigs))
+	groupAddresses := make([]GroupAddressStatus, 0, len(addresses))
+	e.lock.RLock()
+	listener, poller := e.listener, e.poller
+	e.lock.RUnlock()
+	now := time.Now()
+	for _, address := range addresses {
+		groupAddresses = append(groupAddresses, newGroupAddressStatus(e.config, address, snapshots[address], poller, now))
+	}
+	warnings := []Warning{}
+	if listener != nil {
+		warnings = append(warnings, listener.Warnings()...)
+	}
+	return ExporterStatus{
+		Installation:   e.config.Installation,
+		Connection:     connection,
+		GroupAddresses: groupAddresses,
+		Warnings:       warnings,
+	}
+}
+
+func (e *metricsExporter) IsConnected() error {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.connectionError()
+}
+
+// connectionError returns an error if the exporter is not connected. The lock must be held by the caller.
+func (e *metricsExporter) connectionError() error {
+	if e.health != nil {
+		return e.health
+	}
+	if e.client == nil || e.listener == nil || !e.listener.IsActive() {
+		return fmt.Errorf("not connected")
+	}
+	return nil
+}
+
+func (e *metricsExporter) IsStartupReadFinished(timeout time.Duration) error {
+	e.lock.RLock()
+	poller := e.poller
+	e.lock.RUnlock()
+	if poller == nil {
+		return fmt.Errorf("startup reads are not started yet")
+	}
+	if poller.StartupReadFinished() || time.Since(e.startedAt) >= timeout {
+		return nil
+	}
+	return fmt.Errorf("startup reads are not finished yet")
+}
+
+func (e *metricsExporter) HasFreshValues(minPercent float64) error {
+	snapshots := make(map[GroupAddress][]*Snapshot)
+	for _, s := range e.metrics.Snapshots() {
+		snapshots[s.destination] = append(snapshots[s.destination], s)
+	}
+	now := time.Now()
+	total, fresh := 0, 0
+	for address, addressConfig := range e.config.AddressConfigs {
+		if !addressConfig.Export || !addressConfig.ReadActive || addressConfig.MaxAge <= 0 || !inReadWindows(addressConfig.ReadWindows, now) {
+			continue
+		}
+		total++
+		if !newGroupAddressStatus(e.config, address, snapshots[address], nil, now).Stale {
+			fresh++
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	if percent := 100 * float64(fresh) / float64(total); percent < minPercent {
+		return fmt.Errorf("only %d of %d actively read group addresses have a fresh value", fresh, total)
+	}
+	return nil
+}
+
+func (e *metricsExporter) Subscribe(telegrams chan<- TelegramEvent, filter TelegramFilter) func() {
+	return e.telegrams.Subscribe(telegrams, filter)
+}
+
+func (e *metricsExporter) Write(address GroupAddress, value string) error {
+	addressConfig, ok := e.config.AddressConfigs[address]
+	if !ok {
+		return fmt.Errorf("group address %s is not configured", address)
+	}
+	data, err := encodeValue(addressConfig.DPT, value)
+	if err != nil {
+		return err
+	}
+	e.lock.RLock()
+	client := e.client
+	e.lock.RUnlock()
+	if client == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	event := knx.GroupEvent{
+		Command:     knx.GroupWrite,
+		Source:      cemi.IndividualAddr(e.config.Connection.PhysicalAddress),
+		Destination: cemi.GroupAddr(address),
+		Data:        data,
+	}
+	if err = client.Send(event); err != nil {
+		e.messageCounter.WithLabelValues("sent", "false").Inc()
+		return fmt.Errorf("can not send value to %s: %s", address, err)
+	}
+	e.messageCounter.WithLabelValues("sent", "true").Inc()
+	return nil
+}
+
+func (e *metricsExporter) createClient() (GroupClient, error) {
 	switch e.config.Connection.Type {
 	case Tunnel:
 		slog.With(
@@ -103,10 +433,9 @@ func (e *metricsExporter) createClient() error {
 		).Info("Connecting to endpoint")
 		tunnel, err := knx.NewGroupTunnel(e.config.Connection.Endpoint, e.config.Connection.TunnelConfig.toKnxTunnelConfig())
 		if err != nil {
-			return err
+			return nil, err
 		}
-		e.client = &tunnel
-		return nil
+		return &tunnel, nil
 	case Router:
 		slog.With(
 			"endpoint", e.config.Connection.Endpoint,
@@ -115,16 +444,15 @@ func (e *metricsExporter) createClient() error {
 
 		config, err := e.config.Connection.RouterConfig.toKnxRouterConfig()
 		if err != nil {
-			return fmt.Errorf("unable to convert router config: %s", err)
+			return nil, fmt.Errorf("unable to convert router config: %s", err)
 		}
 
 		router, err := knx.NewGroupRouter(e.config.Connection.Endpoint, config)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		e.client = &router
-		return nil
+		return &router, nil
 	default:
-		return fmt.Errorf("invalid connection type. must be either Tunnel or Router")
+		return nil, fmt.Errorf("invalid connection type. must be either Tunnel or Router")
 	}
 }
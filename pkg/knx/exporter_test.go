@@ -16,26 +16,33 @@ package knx
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/vapourismo/knx-go/knx"
 
 	"github.com/chr-fritz/knx-exporter/pkg/metrics/fake"
 )
 
-func TestNewMetricsExporter(t *testing.T) {
+func TestNewMetricsExporters(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	ctx, cancelFunc := context.WithCancel(context.TODO())
 
 	exporter := fake.NewMockExporter(ctrl)
 	exporter.EXPECT().Register(gomock.Any()).AnyTimes()
-	exp, err := NewMetricsExporter("fixtures/readConfig.yaml", exporter)
-	metricsExporter, ok := exp.(*metricsExporter)
-	assert.True(t, ok)
+	exporters, err := NewMetricsExporters("fixtures/readConfig.yaml", exporter)
 	assert.NoError(t, err)
+	assert.Len(t, exporters, 1)
+	metricsExporter, ok := exporters[0].(*metricsExporter)
+	assert.True(t, ok)
 
 	err = metricsExporter.Run(ctx)
 	assert.NoError(t, err)
@@ -48,6 +55,32 @@ func TestNewMetricsExporter(t *testing.T) {
 	cancelFunc()
 }
 
+func TestNewMetricsExporters_Installations(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	exporters, err := NewMetricsExporters("fixtures/installations-config.yaml", registry)
+	assert.NoError(t, err)
+	assert.Len(t, exporters, 2)
+	assert.Equal(t, "building-a", exporters[0].Installation())
+	assert.Equal(t, "building-b", exporters[1].Installation())
+
+	for _, e := range exporters {
+		m := e.(*metricsExporter)
+		m.messageCounter.WithLabelValues("received", "true").Inc()
+	}
+
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "knx_messages" {
+			continue
+		}
+		assert.Len(t, family.GetMetric(), 2)
+		for _, metric := range family.GetMetric() {
+			assert.Contains(t, metric.String(), "installation")
+		}
+	}
+}
+
 func TestMetricsExporter_createClient(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -63,9 +96,143 @@ func TestMetricsExporter_createClient(t *testing.T) {
 			e := &metricsExporter{
 				config: tt.config,
 			}
-			if err := e.createClient(); (err != nil) != tt.wantErr {
+			if _, err := e.createClient(); (err != nil) != tt.wantErr {
 				t.Errorf("createClient() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestNewMetricsExporter_outputMetrics(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	_, err := newMetricsExporter(&Config{
+		Installation: "home",
+		InfluxDB:     &InfluxDBConfig{URL: "http://localhost:8086", Bucket: "knx"},
+	}, registry)
+	assert.NoError(t, err)
+
+	expected := `
+# HELP knx_influxdb_dropped_values Number of values that were dropped instead of written into the InfluxDB.
+# TYPE knx_influxdb_dropped_values counter
+knx_influxdb_dropped_values{installation="home"} 0
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "knx_influxdb_dropped_values"))
+}
+
+func TestMetricsExporter_Reconnect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e, err := newMetricsExporter(&Config{}, prometheus.NewPedanticRegistry())
+	assert.NoError(t, err)
+	var closed atomic.Int32
+	newClient := func() GroupClient {
+		inbound := make(chan knx.GroupEvent)
+		client := NewMockGroupClient(ctrl)
+		client.EXPECT().Inbound().Return(inbound)
+		client.EXPECT().Close().Do(func() {
+			closed.Add(1)
+			close(inbound)
+		})
+		return client
+	}
+	clients := []GroupClient{newClient(), newClient()}
+	var dialErr error
+	e.dial = func() (GroupClient, error) {
+		if dialErr != nil {
+			return nil, dialErr
+		}
+		client := clients[0]
+		clients = clients[1:]
+		return client, nil
+	}
+
+	assert.NoError(t, e.Run(ctx))
+	assert.NoError(t, e.IsConnected())
+
+	oldListener := e.listener
+	assert.NoError(t, e.Reconnect(ctx))
+	assert.Equal(t, int32(1), closed.Load())
+	assert.NoError(t, e.IsConnected())
+	assert.NotSame(t, oldListener, e.listener)
+	assert.Eventually(t, func() bool { return !oldListener.IsActive() }, time.Second, 10*time.Millisecond)
+
+	dialErr = fmt.Errorf("no route to host")
+	assert.EqualError(t, e.Reconnect(ctx), "no route to host")
+	assert.Equal(t, int32(2), closed.Load())
+	assert.EqualError(t, e.IsConnected(), "no route to host")
+}
+
+func TestMetricsExporter_IsConnected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name     string
+		exporter *metricsExporter
+		wantErr  string
+	}{
+		{"connection error", &metricsExporter{health: fmt.Errorf("no route to host")}, "no route to host"},
+		{"not started", &metricsExporter{}, "not connected"},
+		{"connected", &metricsExporter{client: NewMockGroupClient(ctrl), listener: NewListener(&Config{}, nil, nil, nil)}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.exporter.IsConnected()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMetricsExporter_IsStartupReadFinished(t *testing.T) {
+	tests := []struct {
+		name      string
+		finished  bool
+		startedAt time.Time
+		timeout   time.Duration
+		wantErr   bool
+	}{
+		{"running", false, time.Now(), time.Minute, true},
+		{"finished", true, time.Now(), time.Minute, false},
+		{"timed out", false, time.Now().Add(-2 * time.Minute), time.Minute, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPoller(&Config{}, nil, nil).(*poller)
+			p.startupRead.Store(tt.finished)
+			e := &metricsExporter{poller: p, startedAt: tt.startedAt}
+			if err := e.IsStartupReadFinished(tt.timeout); (err != nil) != tt.wantErr {
+				t.Errorf("IsStartupReadFinished() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	assert.Error(t, (&metricsExporter{}).IsStartupReadFinished(time.Minute))
+}
+
+func TestMetricsExporter_HasFreshValues(t *testing.T) {
+	never, err := NewTimeWindow("Sun 00:00-00:01")
+	assert.NoError(t, err)
+	if never.Contains(time.Now()) {
+		t.Skip("the read window must not contain the current time")
+	}
+	config := &Config{AddressConfigs: GroupAddressConfigSet{
+		1: {Name: "fresh", Export: true, ReadActive: true, MaxAge: Duration(time.Minute)},
+		2: {Name: "stale", Export: true, ReadActive: true, MaxAge: Duration(time.Minute)},
+		3: {Name: "missing", Export: true, ReadActive: true, MaxAge: Duration(time.Minute)},
+		4: {Name: "passive", Export: true},
+		5: {Name: "outside window", Export: true, ReadActive: true, MaxAge: Duration(time.Minute), ReadWindows: []TimeWindow{never}},
+	}}
+	e := &metricsExporter{config: config, metrics: NewMetricsSnapshotHandler(1)}
+	e.metrics.AddSnapshot(&Snapshot{name: "fresh", destination: 1, timestamp: time.Now(), config: config.AddressConfigs[1]})
+	e.metrics.AddSnapshot(&Snapshot{name: "stale", destination: 2, timestamp: time.Now().Add(-time.Hour), config: config.AddressConfigs[2]})
+
+	assert.NoError(t, e.HasFreshValues(30))
+	assert.EqualError(t, e.HasFreshValues(50), "only 1 of 3 actively read group addresses have a fresh value")
+	assert.NoError(t, (&metricsExporter{config: &Config{}, metrics: NewMetricsSnapshotHandler(1)}).HasFreshValues(100))
+}
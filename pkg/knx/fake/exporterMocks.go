@@ -21,7 +21,9 @@ package fake
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	knx "github.com/chr-fritz/knx-exporter/pkg/knx"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -48,6 +50,62 @@ func (m *MockMetricsExporter) EXPECT() *MockMetricsExporterMockRecorder {
 	return m.recorder
 }
 
+// Config mocks base method.
+func (m *MockMetricsExporter) Config() *knx.Config {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Config")
+	ret0, _ := ret[0].(*knx.Config)
+	return ret0
+}
+
+// Config indicates an expected call of Config.
+func (mr *MockMetricsExporterMockRecorder) Config() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Config", reflect.TypeOf((*MockMetricsExporter)(nil).Config))
+}
+
+// HasFreshValues mocks base method.
+func (m *MockMetricsExporter) HasFreshValues(minPercent float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasFreshValues", minPercent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HasFreshValues indicates an expected call of HasFreshValues.
+func (mr *MockMetricsExporterMockRecorder) HasFreshValues(minPercent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasFreshValues", reflect.TypeOf((*MockMetricsExporter)(nil).HasFreshValues), minPercent)
+}
+
+// Health mocks base method.
+func (m *MockMetricsExporter) Health() []knx.ComponentHealth {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Health")
+	ret0, _ := ret[0].([]knx.ComponentHealth)
+	return ret0
+}
+
+// Health indicates an expected call of Health.
+func (mr *MockMetricsExporterMockRecorder) Health() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockMetricsExporter)(nil).Health))
+}
+
+// Installation mocks base method.
+func (m *MockMetricsExporter) Installation() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Installation")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Installation indicates an expected call of Installation.
+func (mr *MockMetricsExporterMockRecorder) Installation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Installation", reflect.TypeOf((*MockMetricsExporter)(nil).Installation))
+}
+
 // IsAlive mocks base method.
 func (m *MockMetricsExporter) IsAlive() error {
 	m.ctrl.T.Helper()
@@ -62,6 +120,48 @@ func (mr *MockMetricsExporterMockRecorder) IsAlive() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAlive", reflect.TypeOf((*MockMetricsExporter)(nil).IsAlive))
 }
 
+// IsConnected mocks base method.
+func (m *MockMetricsExporter) IsConnected() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsConnected")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IsConnected indicates an expected call of IsConnected.
+func (mr *MockMetricsExporterMockRecorder) IsConnected() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsConnected", reflect.TypeOf((*MockMetricsExporter)(nil).IsConnected))
+}
+
+// IsStartupReadFinished mocks base method.
+func (m *MockMetricsExporter) IsStartupReadFinished(timeout time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsStartupReadFinished", timeout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IsStartupReadFinished indicates an expected call of IsStartupReadFinished.
+func (mr *MockMetricsExporterMockRecorder) IsStartupReadFinished(timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsStartupReadFinished", reflect.TypeOf((*MockMetricsExporter)(nil).IsStartupReadFinished), timeout)
+}
+
+// Reconnect mocks base method.
+func (m *MockMetricsExporter) Reconnect(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconnect", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reconnect indicates an expected call of Reconnect.
+func (mr *MockMetricsExporterMockRecorder) Reconnect(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconnect", reflect.TypeOf((*MockMetricsExporter)(nil).Reconnect), ctx)
+}
+
 // Run mocks base method.
 func (m *MockMetricsExporter) Run(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -75,3 +175,45 @@ func (mr *MockMetricsExporterMockRecorder) Run(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockMetricsExporter)(nil).Run), ctx)
 }
+
+// Status mocks base method.
+func (m *MockMetricsExporter) Status() knx.ExporterStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].(knx.ExporterStatus)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockMetricsExporterMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockMetricsExporter)(nil).Status))
+}
+
+// Subscribe mocks base method.
+func (m *MockMetricsExporter) Subscribe(telegrams chan<- knx.TelegramEvent, filter knx.TelegramFilter) func() {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", telegrams, filter)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockMetricsExporterMockRecorder) Subscribe(telegrams, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockMetricsExporter)(nil).Subscribe), telegrams, filter)
+}
+
+// Write mocks base method.
+func (m *MockMetricsExporter) Write(address knx.GroupAddress, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", address, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockMetricsExporterMockRecorder) Write(address, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockMetricsExporter)(nil).Write), address, value)
+}
@@ -0,0 +1,201 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// ConvertFilter selects the group addresses of an export which should be converted.
+type ConvertFilter struct {
+	// Include contains the conditions of which at least one must match. If empty, all group addresses are included.
+	Include []FilterCondition `json:",omitempty"`
+	// Exclude contains the conditions of which none must match.
+	Exclude []FilterCondition `json:",omitempty"`
+}
+
+// FilterCondition matches all group addresses which fulfill all of its defined fields.
+type FilterCondition struct {
+	// Range matches either a main group like "1", a middle group like "1/2" or a range of group addresses like
+	// "1/2/0-1/2/50".
+	Range string `json:",omitempty"`
+	// Name is a regular expression which must match the name of the group address within the ETS.
+	Name string `json:",omitempty"`
+	// DPT matches the data point type of the group address. It can either be a specific type like "9.001" or a whole
+	// family like "9.*".
+	DPT string `json:",omitempty"`
+	// Central matches the central flag of the group address if defined.
+	Central *bool `json:",omitempty"`
+	// Unfiltered matches the unfiltered flag of the group address if defined.
+	Unfiltered *bool `json:",omitempty"`
+
+	rangeStart GroupAddress
+	rangeEnd   GroupAddress
+	nameRegex  *regexp.Regexp
+}
+
+// ReadConvertFilter reads the filter file and validates all conditions within it.
+func ReadConvertFilter(filterFile string) (*ConvertFilter, error) {
+	content, err := os.ReadFile(filterFile)
+	if err != nil {
+		return nil, fmt.Errorf("can not read convert filter: %s", err)
+	}
+	filter := &ConvertFilter{}
+	if err = yaml.Unmarshal(content, filter); err != nil {
+		return nil, fmt.Errorf("can not parse convert filter file %s: %s", filterFile, err)
+	}
+	if err = filter.init(); err != nil {
+		return nil, fmt.Errorf("invalid convert filter file %s: %s", filterFile, err)
+	}
+	return filter, nil
+}
+
+func (f *ConvertFilter) init() error {
+	for i := range f.Include {
+		if err := f.Include[i].init(); err != nil {
+			return fmt.Errorf("invalid include condition %d: %s", i, err)
+		}
+	}
+	for i := range f.Exclude {
+		if err := f.Exclude[i].init(); err != nil {
+			return fmt.Errorf("invalid exclude condition %d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+func (c *FilterCondition) init() error {
+	if c.Range != "" {
+		start, end, err := parseAddressRange(c.Range)
+		if err != nil {
+			return err
+		}
+		c.rangeStart, c.rangeEnd = start, end
+	}
+	if c.Name != "" {
+		nameRegex, err := regexp.Compile(c.Name)
+		if err != nil {
+			return fmt.Errorf("invalid name regex: %s", err)
+		}
+		c.nameRegex = nameRegex
+	}
+	return nil
+}
+
+// parseAddressRange parses a main group like "1", a middle group like "1/2" or a range of group addresses like
+// "1/2/0-1/2/50" into its first and last group address.
+func parseAddressRange(str string) (GroupAddress, GroupAddress, error) {
+	if first, last, ok := strings.Cut(str, "-"); ok {
+		start, err := NewGroupAddress(strings.TrimSpace(first))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid group address range \"%s\": %s", str, err)
+		}
+		end, err := NewGroupAddress(strings.TrimSpace(last))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid group address range \"%s\": %s", str, err)
+		}
+		if end < start {
+			return 0, 0, fmt.Errorf("invalid group address range \"%s\": end is lower than start", str)
+		}
+		return start, end, nil
+	}
+
+	parts := strings.Split(str, "/")
+	if len(parts) == 3 {
+		address, err := NewGroupAddress(str)
+		return address, address, err
+	}
+	if len(parts) > 3 {
+		return 0, 0, fmt.Errorf("invalid group range \"%s\"", str)
+	}
+	main, err := strconv.ParseUint(parts[0], 10, 5)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid main group \"%s\": %s", str, err)
+	}
+	if len(parts) == 1 {
+		return GroupAddress(main << 11), GroupAddress(main<<11 | 0x7ff), nil
+	}
+	middle, err := strconv.ParseUint(parts[1], 10, 3)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid middle group \"%s\": %s", str, err)
+	}
+	start := GroupAddress(main<<11 | middle<<8)
+	return start, start | 0xff, nil
+}
+
+// apply returns all group addresses which are selected by the filter together with all group addresses which are
+// filtered out.
+func (f *ConvertFilter) apply(groupAddresses []collectedAddress) ([]collectedAddress, map[GroupAddress]bool) {
+	var selected []collectedAddress
+	filtered := make(map[GroupAddress]bool)
+	for _, ga := range groupAddresses {
+		if f.matches(ga) {
+			selected = append(selected, ga)
+		} else if address, err := NewGroupAddress(ga.Address); err == nil {
+			filtered[address] = true
+		}
+	}
+	return selected, filtered
+}
+
+func (f *ConvertFilter) matches(ga collectedAddress) bool {
+	address, err := NewGroupAddress(ga.Address)
+	if err != nil {
+		return false
+	}
+	dpt, _ := normalizeDPTs(ga.DPTs)
+
+	included := len(f.Include) == 0
+	for _, c := range f.Include {
+		if c.matches(ga, address, dpt) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, c := range f.Exclude {
+		if c.matches(ga, address, dpt) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c FilterCondition) matches(ga collectedAddress, address GroupAddress, dpt string) bool {
+	if c.Range != "" && (address < c.rangeStart || address > c.rangeEnd) {
+		return false
+	}
+	if c.nameRegex != nil && !c.nameRegex.MatchString(ga.Name) {
+		return false
+	}
+	if c.DPT != "" && !matchDPT(c.DPT, dpt) {
+		return false
+	}
+	if c.Central != nil && *c.Central != ga.Central {
+		return false
+	}
+	if c.Unfiltered != nil && *c.Unfiltered != ga.Unfiltered {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chr-fritz/knx-exporter/pkg/knx/export"
+)
+
+func TestConvertGroupAddresses_Filter(t *testing.T) {
+	filter, err := ReadConvertFilter("fixtures/convert-filter.yaml")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		filter *ConvertFilter
+		want   []GroupAddress
+	}{
+		{"no filter", nil, []GroupAddress{1, 2, 2049}},
+		{"filter file", filter, []GroupAddress{1, 2049}},
+		{"exclude dpt family", &ConvertFilter{Exclude: []FilterCondition{{DPT: "1.*"}}}, []GroupAddress{1}},
+		{"include middle group", &ConvertFilter{Include: []FilterCondition{{Range: "1/0"}}}, []GroupAddress{2049}},
+		{"include name", &ConvertFilter{Include: []FilterCondition{{Name: "^Light"}}}, []GroupAddress{2, 2049}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "")
+			assert.NoError(t, err)
+			defer func() {
+				_ = os.Remove(tmpFile.Name())
+			}()
+
+			summary, err := ConvertGroupAddresses("export/fixtures/ga-export-1-1.csv", tmpFile.Name(), ConvertOptions{Filter: tt.filter})
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, summary.Added)
+
+			content, err := os.ReadFile(tmpFile.Name())
+			assert.NoError(t, err)
+			config := struct{ AddressConfigs GroupAddressConfigSet }{}
+			assert.NoError(t, yaml.Unmarshal(content, &config))
+			assert.Len(t, config.AddressConfigs, len(tt.want))
+		})
+	}
+}
+
+func TestReadConvertFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"valid", "Include: [{Range: \"1/2\", Name: \"^Temp\", DPT: 9.*}]\nExclude: [{Central: true}]", false},
+		{"invalid include range", "Include: [{Range: \"32\"}]", true},
+		{"invalid exclude name", "Exclude: [{Name: \"(\"}]", true},
+		{"not existing", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filterFile := "fixtures/not-existing-filter.yaml"
+			if tt.name != "not existing" {
+				tmpFile, err := os.CreateTemp("", "")
+				assert.NoError(t, err)
+				defer func() {
+					_ = os.Remove(tmpFile.Name())
+				}()
+				assert.NoError(t, os.WriteFile(tmpFile.Name(), []byte(tt.content), 0600))
+				filterFile = tmpFile.Name()
+			}
+
+			_, err := ReadConvertFilter(filterFile)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadConvertFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_parseAddressRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		str       string
+		wantStart GroupAddress
+		wantEnd   GroupAddress
+		wantErr   bool
+	}{
+		{"main group", "1", 0x0800, 0x0fff, false},
+		{"middle group", "1/2", 0x0a00, 0x0aff, false},
+		{"single address", "1/2/3", 0x0a03, 0x0a03, false},
+		{"address range", "1/2/0 - 1/3/50", 0x0a00, 0x0b32, false},
+		{"reverse range", "1/3/0-1/2/0", 0, 0, true},
+		{"invalid main group", "32", 0, 0, true},
+		{"invalid middle group", "1/8", 0, 0, true},
+		{"invalid range start", "a-1/2/3", 0, 0, true},
+		{"invalid range end", "1/2/3-b", 0, 0, true},
+		{"too many levels", "1/2/3/4", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := parseAddressRange(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseAddressRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestFilterCondition_matches(t *testing.T) {
+	enabled := true
+	disabled := false
+	ga := collectedAddress{GroupAddress: export.GroupAddress{Name: "Light kitchen", Address: "1/2/3", Central: true, DPTs: "DPST-1-1"}}
+	tests := []struct {
+		name      string
+		condition FilterCondition
+		want      bool
+	}{
+		{"empty", FilterCondition{}, true},
+		{"range", FilterCondition{Range: "1/2"}, true},
+		{"other range", FilterCondition{Range: "1/3"}, false},
+		{"name", FilterCondition{Name: "kitchen$"}, true},
+		{"other name", FilterCondition{Name: "^kitchen"}, false},
+		{"dpt family", FilterCondition{DPT: "1.*"}, true},
+		{"other dpt", FilterCondition{DPT: "1.002"}, false},
+		{"central", FilterCondition{Central: &enabled}, true},
+		{"not central", FilterCondition{Central: &disabled}, false},
+		{"not unfiltered", FilterCondition{Unfiltered: &disabled}, true},
+		{"all", FilterCondition{Range: "1", Name: "Light", DPT: "1.001", Central: &enabled, Unfiltered: &disabled}, true},
+		{"one mismatch", FilterCondition{Range: "1", Name: "Light", DPT: "9.*"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, tt.condition.init())
+			assert.Equal(t, tt.want, tt.condition.matches(ga, 0x0a03, "1.001"))
+		})
+	}
+}
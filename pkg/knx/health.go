@@ -0,0 +1,145 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ComponentState is the state of a single component of an exporter.
+type ComponentState string
+
+const ComponentUp = ComponentState("up")
+const ComponentDown = ComponentState("down")
+
+// Names of the components of every exporter. Additionally, every snapshot output is a component named by its type.
+const (
+	ComponentConnection      = "connection"
+	ComponentListener        = "listener"
+	ComponentPoller          = "poller"
+	ComponentSnapshotHandler = "snapshotHandler"
+)
+
+// ComponentHealth describes the health of a single component of an exporter.
+type ComponentHealth struct {
+	Installation string         `json:"installation,omitempty"`
+	Component    string         `json:"component"`
+	State        ComponentState `json:"state"`
+	// Recoverable is true if the component is bound to the connection and can be recovered by reconnecting to the
+	// KNX gateway.
+	Recoverable bool `json:"recoverable"`
+	// LastError is the latest error of the component. It is kept after the component is up again.
+	LastError string `json:"lastError,omitempty"`
+	// LastStateChange is the time when the state was changed or observed for the first time.
+	LastStateChange time.Time `json:"lastStateChange"`
+	// LastTelegram is the time when the component has received or sent the latest telegram.
+	LastTelegram *time.Time `json:"lastTelegram,omitempty"`
+}
+
+// healthTracker keeps the latest health of all components of an exporter to detect state changes.
+type healthTracker struct {
+	installation string
+	lock         sync.Mutex
+	components   map[string]*ComponentHealth
+	now          func() time.Time
+}
+
+func newHealthTracker(installation string) *healthTracker {
+	return &healthTracker{
+		installation: installation,
+		components:   make(map[string]*ComponentHealth),
+		now:          time.Now,
+	}
+}
+
+// update records the current state of the component and returns its health.
+func (t *healthTracker) update(component string, recoverable bool, err error, lastTelegram time.Time) ComponentHealth {
+	state := ComponentUp
+	if err != nil {
+		state = ComponentDown
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	health, ok := t.components[component]
+	if !ok {
+		health = &ComponentHealth{
+			Installation:    t.installation,
+			Component:       component,
+			Recoverable:     recoverable,
+			State:           state,
+			LastStateChange: t.now(),
+		}
+		t.components[component] = health
+	} else if health.State != state {
+		health.State = state
+		health.LastStateChange = t.now()
+	}
+	if err != nil {
+		health.LastError = err.Error()
+	}
+	health.LastTelegram = nil
+	if !lastTelegram.IsZero() {
+		health.LastTelegram = &lastTelegram
+	}
+	return *health
+}
+
+var componentUpDesc = prometheus.NewDesc(
+	"knx_component_up",
+	"Indicates whether a component of the exporter is up (1) or down (0).",
+	[]string{"component"},
+	nil,
+)
+
+// healthCollector exports the state of all components of an exporter.
+type healthCollector struct {
+	exporter MetricsExporter
+}
+
+func (c *healthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- componentUpDesc
+}
+
+func (c *healthCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, component := range c.exporter.Health() {
+		value := 0.0
+		if component.State == ComponentUp {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(componentUpDesc, prometheus.GaugeValue, value, component.Component)
+	}
+}
+
+// writeHealth writes the health of all components of the exporters. The status code is 503 if any component is down.
+func writeHealth(exporters []MetricsExporter, w http.ResponseWriter) {
+	components := []ComponentHealth{}
+	status := http.StatusOK
+	for _, exporter := range exporters {
+		for _, component := range exporter.Health() {
+			if component.State == ComponentDown {
+				status = http.StatusServiceUnavailable
+			}
+			components = append(components, component)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	writeJSON(w, components)
+}
@@ -0,0 +1,165 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_healthTracker_update(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	lastTelegram := start.Add(-time.Minute)
+	tests := []struct {
+		name                string
+		err                 error
+		lastTelegram        time.Time
+		wantState           ComponentState
+		wantLastError       string
+		wantLastStateChange time.Time
+		wantLastTelegram    *time.Time
+	}{
+		{"first observation", nil, time.Time{}, ComponentUp, "", start, nil},
+		{"still up", nil, lastTelegram, ComponentUp, "", start, &lastTelegram},
+		{"down", fmt.Errorf("listener is closed"), lastTelegram, ComponentDown, "listener is closed", start.Add(2 * time.Second), &lastTelegram},
+		{"still down", fmt.Errorf("listener is closed"), lastTelegram, ComponentDown, "listener is closed", start.Add(2 * time.Second), &lastTelegram},
+		{"up again", nil, lastTelegram, ComponentUp, "listener is closed", start.Add(4 * time.Second), &lastTelegram},
+	}
+	tracker := newHealthTracker("home")
+	now := start
+	tracker.now = func() time.Time { return now }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tracker.update(ComponentListener, true, tt.err, tt.lastTelegram)
+			assert.Equal(t, ComponentHealth{
+				Installation:    "home",
+				Component:       ComponentListener,
+				State:           tt.wantState,
+				Recoverable:     true,
+				LastError:       tt.wantLastError,
+				LastStateChange: tt.wantLastStateChange,
+				LastTelegram:    tt.wantLastTelegram,
+			}, got)
+			now = now.Add(time.Second)
+		})
+	}
+}
+
+func TestMetricsExporter_Health(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	output := NewMockSnapshotOutput(ctrl)
+	output.EXPECT().Name().Return("mqtt").AnyTimes()
+	output.EXPECT().IsActive().Return(false).AnyTimes()
+	p := NewPoller(&Config{}, nil, nil).(*poller)
+	p.lastError = fmt.Errorf("connection closed")
+	p.failures = maxPollerFailures
+	l := NewListener(&Config{}, nil, nil, nil).(*listener)
+	l.active.Store(false)
+	e := &metricsExporter{
+		config:     &Config{},
+		client:     NewMockGroupClient(ctrl),
+		metrics:    NewMetricsSnapshotHandler(1),
+		listener:   l,
+		poller:     p,
+		outputs:    []SnapshotOutput{output},
+		components: newHealthTracker(""),
+	}
+
+	states := make(map[string]ComponentState)
+	for _, component := range e.Health() {
+		states[component.Component] = component.State
+	}
+	assert.Equal(t, map[string]ComponentState{
+		ComponentConnection:      ComponentDown,
+		ComponentListener:        ComponentDown,
+		ComponentPoller:          ComponentDown,
+		ComponentSnapshotHandler: ComponentUp,
+		"mqtt":                   ComponentDown,
+	}, states)
+	assert.EqualError(t, e.IsAlive(), "connection: not connected, listener: listener is closed, mqtt: snapshot output is closed")
+
+	expected := `
+# HELP knx_component_up Indicates whether a component of the exporter is up (1) or down (0).
+# TYPE knx_component_up gauge
+knx_component_up{component="connection"} 0
+knx_component_up{component="listener"} 0
+knx_component_up{component="mqtt"} 0
+knx_component_up{component="poller"} 0
+knx_component_up{component="snapshotHandler"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(&healthCollector{exporter: e}, strings.NewReader(expected)))
+}
+
+func TestNewAPIHandler_health(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newExporter := func(installation string, active bool) *metricsExporter {
+		l := NewListener(&Config{}, nil, nil, nil).(*listener)
+		l.active.Store(active)
+		return &metricsExporter{
+			config:     &Config{Installation: installation},
+			client:     NewMockGroupClient(ctrl),
+			metrics:    NewMetricsSnapshotHandler(1),
+			listener:   l,
+			poller:     NewPoller(&Config{}, nil, nil),
+			components: newHealthTracker(installation),
+		}
+	}
+	server := httptest.NewServer(NewAPIHandler([]MetricsExporter{newExporter("home", true), newExporter("office", false)}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantDown   []string
+	}{
+		{"all installations", "", http.StatusServiceUnavailable, []string{"office/connection", "office/listener"}},
+		{"healthy installation", "?installation=home", http.StatusOK, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := http.Get(server.URL + "/api/v1/health" + tt.query)
+			assert.NoError(t, err)
+			defer func() {
+				_ = response.Body.Close()
+			}()
+			assert.Equal(t, tt.wantStatus, response.StatusCode)
+			assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+
+			var components []ComponentHealth
+			assert.NoError(t, json.NewDecoder(response.Body).Decode(&components))
+			var down []string
+			for _, component := range components {
+				if component.State == ComponentDown {
+					down = append(down, component.Installation+"/"+component.Component)
+				}
+			}
+			assert.Equal(t, tt.wantDown, down)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/vapourismo/knx-go/knx/dpt"
+)
+
+const defaultDiscoveryPrefix = "homeassistant"
+
+// discoveryConfig is the payload of a Home Assistant MQTT discovery message.
+type discoveryConfig struct {
+	Name              string          `json:"name"`
+	UniqueID          string          `json:"unique_id"`
+	ObjectID          string          `json:"object_id"`
+	StateTopic        string          `json:"state_topic"`
+	ValueTemplate     string          `json:"value_template"`
+	UnitOfMeasurement string          `json:"unit_of_measurement,omitempty"`
+	DeviceClass       string          `json:"device_class,omitempty"`
+	StateClass        string          `json:"state_class,omitempty"`
+	PayloadOn         string          `json:"payload_on,omitempty"`
+	PayloadOff        string          `json:"payload_off,omitempty"`
+	Device            discoveryDevice `json:"device"`
+}
+
+type discoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+}
+
+// sensorClass contains the Home Assistant classes and unit of a data point type.
+type sensorClass struct {
+	deviceClass string
+	stateClass  string
+	// unit overrides the unit of the data point type if Home Assistant expects another one.
+	unit string
+}
+
+// sensorClasses maps the data point types to the Home Assistant device and state classes. The specific types are
+// preferred over the families.
+var sensorClasses = map[string]sensorClass{
+	"1.005":  {deviceClass: "safety"},
+	"1.009":  {deviceClass: "opening"},
+	"1.018":  {deviceClass: "occupancy"},
+	"1.019":  {deviceClass: "window"},
+	"5.001":  {stateClass: "measurement"},
+	"9.001":  {deviceClass: "temperature", stateClass: "measurement"},
+	"9.004":  {deviceClass: "illuminance", stateClass: "measurement", unit: "lx"},
+	"9.005":  {deviceClass: "wind_speed", stateClass: "measurement"},
+	"9.006":  {deviceClass: "pressure", stateClass: "measurement"},
+	"9.007":  {deviceClass: "humidity", stateClass: "measurement"},
+	"9.008":  {deviceClass: "carbon_dioxide", stateClass: "measurement"},
+	"9.020":  {deviceClass: "voltage", stateClass: "measurement"},
+	"9.021":  {deviceClass: "current", stateClass: "measurement"},
+	"9.024":  {deviceClass: "power", stateClass: "measurement"},
+	"13.010": {deviceClass: "energy", stateClass: "total_increasing"},
+	"13.013": {deviceClass: "energy", stateClass: "total_increasing"},
+	"14.019": {deviceClass: "current", stateClass: "measurement"},
+	"14.027": {deviceClass: "voltage", stateClass: "measurement"},
+	"14.056": {deviceClass: "power", stateClass: "measurement"},
+}
+
+// publishDiscovery publishes the retained Home Assistant discovery messages of all exported group addresses.
+func (o *mqttOutput) publishDiscovery() {
+	var addresses []GroupAddress
+	for address, cfg := range o.config.AddressConfigs {
+		if cfg.Export {
+			addresses = append(addresses, address)
+		}
+	}
+	slices.Sort(addresses)
+
+	for _, address := range addresses {
+		topic, discovery := o.newDiscoveryConfig(address, o.config.AddressConfigs[address])
+		payload, err := json.Marshal(discovery)
+		if err != nil {
+			o.logger.Warn("Can not marshal discovery message: " + err.Error())
+			continue
+		}
+		o.send(topic, true, payload)
+	}
+	o.logger.Info("Published home assistant discovery messages", "count", len(addresses))
+}
+
+// newDiscoveryConfig returns the discovery topic and message of the group address. Boolean values are published as
+// binary sensors, all other values as sensors.
+func (o *mqttOutput) newDiscoveryConfig(address GroupAddress, cfg *GroupAddressConfig) (string, discoveryConfig) {
+	name := o.config.NameFor(cfg)
+	objectID := name + "_" + strings.ReplaceAll(address.String(), "/", "_")
+	deviceName := "KNX"
+	if o.config.Installation != "" {
+		deviceName += " " + o.config.Installation
+	}
+	discovery := discoveryConfig{
+		Name:          name,
+		UniqueID:      o.clientID + "_" + objectID,
+		ObjectID:      objectID,
+		StateTopic:    o.topicForAddress(address, cfg),
+		ValueTemplate: "{{ value_json.value }}",
+		Device: discoveryDevice{
+			Identifiers:  []string{o.clientID},
+			Name:         deviceName,
+			Manufacturer: "knx-exporter",
+		},
+	}
+
+	class, ok := sensorClasses[cfg.DPT]
+	if !ok && strings.ToLower(cfg.MetricType) == "counter" {
+		class.stateClass = "total_increasing"
+	}
+	discovery.DeviceClass = class.deviceClass
+
+	component := "sensor"
+	if strings.HasPrefix(cfg.DPT, "1.") {
+		component = "binary_sensor"
+		discovery.ValueTemplate = "{{ value_json.value | int }}"
+		discovery.PayloadOn = "1"
+		discovery.PayloadOff = "0"
+	} else {
+		discovery.StateClass = class.stateClass
+		discovery.UnitOfMeasurement = class.unit
+		if datapoint, found := dpt.Produce(cfg.DPT); found && class.unit == "" {
+			discovery.UnitOfMeasurement = datapoint.Unit()
+		}
+	}
+
+	prefix := o.config.MQTT.DiscoveryPrefix
+	if prefix == "" {
+		prefix = defaultDiscoveryPrefix
+	}
+	return prefix + "/" + component + "/" + o.clientID + "/" + objectID + "/config", discovery
+}
@@ -0,0 +1,191 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMQTTOutput_newDiscoveryConfig(t *testing.T) {
+	device := discoveryDevice{Identifiers: []string{"knx-exporter"}, Name: "KNX", Manufacturer: "knx-exporter"}
+	tests := []struct {
+		name      string
+		address   GroupAddress
+		cfg       *GroupAddressConfig
+		wantTopic string
+		want      discoveryConfig
+	}{
+		{
+			"temperature",
+			GroupAddress(1),
+			&GroupAddressConfig{Name: "temperature", DPT: "9.001", MetricType: "gauge", Export: true},
+			"homeassistant/sensor/knx-exporter/knx_temperature_0_0_1/config",
+			discoveryConfig{
+				Name:              "knx_temperature",
+				UniqueID:          "knx-exporter_knx_temperature_0_0_1",
+				ObjectID:          "knx_temperature_0_0_1",
+				StateTopic:        "knx/0/0/1",
+				ValueTemplate:     "{{ value_json.value }}",
+				UnitOfMeasurement: "°C",
+				DeviceClass:       "temperature",
+				StateClass:        "measurement",
+				Device:            device,
+			},
+		},
+		{
+			"energy",
+			GroupAddress(2),
+			&GroupAddressConfig{Name: "energy", DPT: "13.010", MetricType: "counter", Export: true, MQTTTopic: "home/energy"},
+			"homeassistant/sensor/knx-exporter/knx_energy_0_0_2/config",
+			discoveryConfig{
+				Name:              "knx_energy",
+				UniqueID:          "knx-exporter_knx_energy_0_0_2",
+				ObjectID:          "knx_energy_0_0_2",
+				StateTopic:        "home/energy",
+				ValueTemplate:     "{{ value_json.value }}",
+				UnitOfMeasurement: "Wh",
+				DeviceClass:       "energy",
+				StateClass:        "total_increasing",
+				Device:            device,
+			},
+		},
+		{
+			"illuminance",
+			GroupAddress(3),
+			&GroupAddressConfig{Name: "brightness", DPT: "9.004", MetricType: "gauge", Export: true},
+			"homeassistant/sensor/knx-exporter/knx_brightness_0_0_3/config",
+			discoveryConfig{
+				Name:              "knx_brightness",
+				UniqueID:          "knx-exporter_knx_brightness_0_0_3",
+				ObjectID:          "knx_brightness_0_0_3",
+				StateTopic:        "knx/0/0/3",
+				ValueTemplate:     "{{ value_json.value }}",
+				UnitOfMeasurement: "lx",
+				DeviceClass:       "illuminance",
+				StateClass:        "measurement",
+				Device:            device,
+			},
+		},
+		{
+			"unknown counter",
+			GroupAddress(4),
+			&GroupAddressConfig{Name: "pulses", DPT: "12.001", MetricType: "counter", Export: true},
+			"homeassistant/sensor/knx-exporter/knx_pulses_0_0_4/config",
+			discoveryConfig{
+				Name:              "knx_pulses",
+				UniqueID:          "knx-exporter_knx_pulses_0_0_4",
+				ObjectID:          "knx_pulses_0_0_4",
+				StateTopic:        "knx/0/0/4",
+				ValueTemplate:     "{{ value_json.value }}",
+				UnitOfMeasurement: "pulses",
+				StateClass:        "total_increasing",
+				Device:            device,
+			},
+		},
+		{
+			"window",
+			GroupAddress(5),
+			&GroupAddressConfig{Name: "window", DPT: "1.019", MetricType: "gauge", Export: true},
+			"homeassistant/binary_sensor/knx-exporter/knx_window_0_0_5/config",
+			discoveryConfig{
+				Name:          "knx_window",
+				UniqueID:      "knx-exporter_knx_window_0_0_5",
+				ObjectID:      "knx_window_0_0_5",
+				StateTopic:    "knx/0/0/5",
+				ValueTemplate: "{{ value_json.value | int }}",
+				DeviceClass:   "window",
+				PayloadOn:     "1",
+				PayloadOff:    "0",
+				Device:        device,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewMQTTOutput(&Config{MetricsPrefix: "knx_", MQTT: &MQTTConfig{Broker: "tcp://localhost:1883"}})
+			assert.NoError(t, err)
+
+			topic, got := output.(*mqttOutput).newDiscoveryConfig(tt.address, tt.cfg)
+			assert.Equal(t, tt.wantTopic, topic)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMQTTOutput_publishDiscovery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	broker, _ := startBroker(t, false)
+
+	config := &Config{
+		MetricsPrefix: "knx_",
+		Installation:  "home",
+		AddressConfigs: GroupAddressConfigSet{
+			GroupAddress(1): {Name: "temperature", DPT: "9.001", Export: true},
+			GroupAddress(2): {Name: "not_exported", DPT: "9.001"},
+		},
+		MQTT: &MQTTConfig{Broker: "tcp://" + broker, HomeAssistantDiscovery: true, DiscoveryPrefix: "ha"},
+	}
+	output, err := NewMQTTOutput(config)
+	assert.NoError(t, err)
+
+	// Subscribe before publishing. The discovery is published on every connect and retained, so the same topic can be
+	// received multiple times.
+	subscriber := mqtt.NewClient(mqtt.NewClientOptions().AddBroker("tcp://" + broker).SetClientID("test-subscriber"))
+	token := subscriber.Connect()
+	assert.True(t, token.WaitTimeout(5*time.Second))
+	assert.NoError(t, token.Error())
+	defer subscriber.Disconnect(0)
+
+	messages := make(chan mqtt.Message, 10)
+	subscriber.Subscribe("ha/#", 1, func(_ mqtt.Client, message mqtt.Message) {
+		messages <- message
+	}).Wait()
+	go output.Run(ctx)
+
+	received := make(map[string]mqtt.Message)
+	timeout := time.After(5 * time.Second)
+	for len(received) == 0 {
+		select {
+		case message := <-messages:
+			received[message.Topic()] = message
+		case <-timeout:
+			t.Fatal("no discovery message received")
+		}
+	}
+	for collecting := true; collecting; {
+		select {
+		case message := <-messages:
+			received[message.Topic()] = message
+		case <-time.After(200 * time.Millisecond):
+			collecting = false
+		}
+	}
+
+	assert.Len(t, received, 1)
+	message, ok := received["ha/sensor/knx-exporter-home/knx_temperature_0_0_1/config"]
+	if assert.True(t, ok, "no discovery message for the exported group address") {
+		var discovery discoveryConfig
+		assert.NoError(t, json.Unmarshal(message.Payload(), &discovery))
+		assert.Equal(t, "knx/home/0/0/1", discovery.StateTopic)
+		assert.Equal(t, "KNX home", discovery.Device.Name)
+	}
+}
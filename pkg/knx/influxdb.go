@@ -0,0 +1,458 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const defaultInfluxBatchSize = 500
+const defaultInfluxFlushInterval = 10 * time.Second
+const defaultInfluxTimeout = 10 * time.Second
+const defaultInfluxMaxRetries = 3
+const defaultInfluxMaxBufferedBatches = 1000
+
+// influxPendingBatches is the number of batches which can wait for the writer before they are buffered or dropped.
+const influxPendingBatches = 4
+
+// influxBufferExtension is the file extension of the batches which are buffered within the BufferDir.
+const influxBufferExtension = ".lp"
+
+// errPermanent marks write errors which can not be solved by retrying the same batch.
+type errPermanent struct {
+	err error
+}
+
+func (e errPermanent) Error() string {
+	return e.err.Error()
+}
+
+type influxOutput struct {
+	config        *Config
+	writeURL      string
+	client        *http.Client
+	snapshots     chan *Snapshot
+	batch         []string
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    uint
+	retryInterval time.Duration
+	maxBuffered   int
+	bufferLock    sync.Mutex
+	dropped       prometheus.Counter
+	active        atomic.Bool
+	logger        *slog.Logger
+}
+
+// NewInfluxDBOutput creates a SnapshotOutput which writes the values of all exported group addresses in batches into
+// the InfluxDB defined within the configuration.
+func NewInfluxDBOutput(config *Config) (SnapshotOutput, error) {
+	influxConfig := config.InfluxDB
+	writeURL, err := influxWriteURL(influxConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if influxConfig.TLS != nil {
+		tlsConfig, err := influxConfig.TLS.toTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid influxdb tls config: %s", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	timeout := time.Duration(influxConfig.Timeout)
+	if timeout == 0 {
+		timeout = defaultInfluxTimeout
+	}
+
+	if influxConfig.BufferDir != "" {
+		if err = os.MkdirAll(influxConfig.BufferDir, 0750); err != nil {
+			return nil, fmt.Errorf("can not create influxdb buffer directory: %s", err)
+		}
+	}
+
+	logger := slog.With("url", influxConfig.URL)
+	if config.Installation != "" {
+		logger = logger.With("installation", config.Installation)
+	}
+	output := &influxOutput{
+		config:        config,
+		writeURL:      writeURL,
+		client:        &http.Client{Transport: transport, Timeout: timeout},
+		batchSize:     int(influxConfig.BatchSize),
+		flushInterval: time.Duration(influxConfig.FlushInterval),
+		maxRetries:    defaultInfluxMaxRetries,
+		retryInterval: time.Second,
+		maxBuffered:   int(influxConfig.MaxBufferedBatches),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "knx",
+			Name:      "influxdb_dropped_values",
+			Help:      "Number of values that were dropped instead of written into the InfluxDB.",
+		}),
+		logger: logger,
+	}
+	output.active.Store(true)
+	if output.batchSize == 0 {
+		output.batchSize = defaultInfluxBatchSize
+	}
+	if output.flushInterval == 0 {
+		output.flushInterval = defaultInfluxFlushInterval
+	}
+	if influxConfig.MaxRetries != nil {
+		output.maxRetries = *influxConfig.MaxRetries
+	}
+	if output.maxBuffered == 0 {
+		output.maxBuffered = defaultInfluxMaxBufferedBatches
+	}
+	output.snapshots = make(chan *Snapshot, 2*output.batchSize)
+	return output, nil
+}
+
+// influxWriteURL returns the URL of the v1 or v2 write API including all query parameters.
+func influxWriteURL(config *InfluxDBConfig) (string, error) {
+	base, err := url.Parse(config.URL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return "", fmt.Errorf("invalid influxdb url \"%s\"", config.URL)
+	}
+	query := url.Values{"precision": []string{"ns"}}
+	switch config.Version {
+	case 0, 2:
+		if config.Bucket == "" {
+			return "", fmt.Errorf("influxdb bucket is required for the v2 write api")
+		}
+		base = base.JoinPath("api", "v2", "write")
+		query.Set("org", config.Org)
+		query.Set("bucket", config.Bucket)
+	case 1:
+		if config.Database == "" {
+			return "", fmt.Errorf("influxdb database is required for the v1 write api")
+		}
+		base = base.JoinPath("write")
+		query.Set("db", config.Database)
+		if config.RetentionPolicy != "" {
+			query.Set("rp", config.RetentionPolicy)
+		}
+	default:
+		return "", fmt.Errorf("invalid influxdb version %d: must be 1 or 2", config.Version)
+	}
+	base.RawQuery = query.Encode()
+	return base.String(), nil
+}
+
+func (o *influxOutput) Publish(snapshot *Snapshot) {
+	// The line protocol does not support NaN and infinite values.
+	if !snapshot.config.Export || math.IsNaN(snapshot.value) || math.IsInf(snapshot.value, 0) {
+		return
+	}
+	select {
+	case o.snapshots <- snapshot:
+	default:
+		o.dropped.Inc()
+		o.logger.Debug("Drop value as the influxdb buffer is full", "destination", snapshot.destination)
+	}
+}
+
+// Run collects the values into batches and passes them to a separate writer. So the intake of new values is not
+// blocked while the writer retries a batch.
+func (o *influxOutput) Run(ctx context.Context) {
+	o.active.Store(true)
+	defer func() { o.active.Store(false) }()
+
+	batches := make(chan []byte, influxPendingBatches)
+	written := make(chan struct{})
+	go o.writeBatches(ctx, batches, written)
+	defer func() {
+		close(batches)
+		<-written
+	}()
+
+	ticker := time.NewTicker(o.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case snapshot := <-o.snapshots:
+			o.batch = append(o.batch, o.toLine(snapshot))
+			if len(o.batch) >= o.batchSize {
+				o.handOver(batches)
+			}
+		case <-ticker.C:
+			o.handOver(batches)
+		case <-ctx.Done():
+			// The writer tries to write the remaining values once without retries or keeps them within the buffer.
+			batches <- o.takeBatch()
+			return
+		}
+	}
+}
+
+// writeBatches flushes the batches until the channel is closed.
+func (o *influxOutput) writeBatches(ctx context.Context, batches <-chan []byte, written chan<- struct{}) {
+	defer close(written)
+	for content := range batches {
+		o.flush(ctx, content)
+	}
+}
+
+// handOver passes the current batch to the writer. If the writer is still busy with the pending batches, the batch is
+// buffered or dropped instead of waiting for it. An empty batch lets the writer retry the buffered batches.
+func (o *influxOutput) handOver(batches chan<- []byte) {
+	content := o.takeBatch()
+	select {
+	case batches <- content:
+	default:
+		if len(content) > 0 {
+			o.buffer(content)
+		}
+	}
+}
+
+// takeBatch returns the current batch in the line protocol and starts a new one.
+func (o *influxOutput) takeBatch() []byte {
+	if len(o.batch) == 0 {
+		return nil
+	}
+	content := []byte(strings.Join(o.batch, "\n") + "\n")
+	o.batch = nil
+	return content
+}
+
+func (o *influxOutput) IsActive() bool {
+	return o.active.Load()
+}
+
+func (o *influxOutput) Name() string {
+	return "influxdb"
+}
+
+func (o *influxOutput) Describe(ch chan<- *prometheus.Desc) {
+	o.dropped.Describe(ch)
+}
+
+func (o *influxOutput) Collect(ch chan<- prometheus.Metric) {
+	o.dropped.Collect(ch)
+}
+
+// flush writes all buffered batches and the given batch. If a batch can not be written, it and all following batches
+// are stored within the buffer directory.
+func (o *influxOutput) flush(ctx context.Context, content []byte) {
+	reachable := true
+	for _, file := range o.bufferedBatches() {
+		o.bufferLock.Lock()
+		buffered, err := os.ReadFile(file)
+		o.bufferLock.Unlock()
+		if os.IsNotExist(err) {
+			// The batch was dropped as the buffer is full.
+			continue
+		} else if err != nil {
+			o.logger.Warn("Can not read buffered batch: " + err.Error())
+			continue
+		}
+		if err = o.writeWithRetries(ctx, buffered); err != nil {
+			if _, permanent := err.(errPermanent); !permanent {
+				reachable = false
+				break
+			}
+			o.dropped.Add(countLines(buffered))
+			o.logger.Warn("Drop buffered batch as it was rejected: " + err.Error())
+		}
+		o.bufferLock.Lock()
+		if err = os.Remove(file); err != nil && !os.IsNotExist(err) {
+			o.logger.Warn("Can not remove buffered batch: " + err.Error())
+		}
+		o.bufferLock.Unlock()
+	}
+
+	if len(content) == 0 {
+		return
+	}
+	if reachable {
+		err := o.writeWithRetries(ctx, content)
+		if err == nil {
+			return
+		}
+		if _, permanent := err.(errPermanent); permanent {
+			o.dropped.Add(countLines(content))
+			o.logger.Warn("Drop batch as it was rejected: " + err.Error())
+			return
+		}
+		o.logger.Warn("Can not write batch: " + err.Error())
+	}
+	o.buffer(content)
+}
+
+// writeWithRetries writes the content and retries it with an exponential backoff if it fails temporarily.
+func (o *influxOutput) writeWithRetries(ctx context.Context, content []byte) error {
+	interval := o.retryInterval
+	var err error
+	for attempt := uint(0); ; attempt++ {
+		err = o.write(ctx, content)
+		if err == nil {
+			return nil
+		}
+		if _, permanent := err.(errPermanent); permanent || attempt >= o.maxRetries {
+			return err
+		}
+		o.logger.Debug("Retry writing batch", "attempt", attempt+1, "error", err)
+		select {
+		case <-time.After(interval):
+			interval *= 2
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+func (o *influxOutput) write(ctx context.Context, content []byte) error {
+	// A running write is not canceled on shutdown. Otherwise, the batch would be lost if the InfluxDB has received it.
+	request, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, o.writeURL, bytes.NewReader(content))
+	if err != nil {
+		return errPermanent{fmt.Errorf("can not create write request: %s", err)}
+	}
+	request.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	influxConfig := o.config.InfluxDB
+	if influxConfig.Token != "" {
+		request.Header.Set("Authorization", "Token "+influxConfig.Token)
+	}
+	if influxConfig.Username != "" {
+		request.SetBasicAuth(influxConfig.Username, influxConfig.Password)
+	}
+
+	response, err := o.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("can not write batch: %s", err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	err = fmt.Errorf("influxdb responded with %s: %s", response.Status, strings.TrimSpace(string(body)))
+	if response.StatusCode >= 400 && response.StatusCode < 500 && response.StatusCode != http.StatusTooManyRequests {
+		return errPermanent{err}
+	}
+	return err
+}
+
+// buffer stores the batch within the buffer directory and drops the oldest batches if there are too many.
+func (o *influxOutput) buffer(content []byte) {
+	bufferDir := o.config.InfluxDB.BufferDir
+	if bufferDir == "" {
+		o.dropped.Add(countLines(content))
+		o.logger.Warn("Drop batch as no buffer directory is configured", "values", countLines(content))
+		return
+	}
+
+	o.bufferLock.Lock()
+	defer o.bufferLock.Unlock()
+	file := filepath.Join(bufferDir, fmt.Sprintf("%020d%s", time.Now().UnixNano(), influxBufferExtension))
+	if err := os.WriteFile(file, content, 0640); err != nil {
+		o.logger.Warn("Can not buffer batch: " + err.Error())
+		return
+	}
+
+	files := o.bufferedBatches()
+	for len(files) > o.maxBuffered {
+		o.logger.Warn("Drop oldest buffered batch as the buffer is full", "file", files[0])
+		if oldest, err := os.ReadFile(files[0]); err == nil {
+			o.dropped.Add(countLines(oldest))
+		}
+		if err := os.Remove(files[0]); err != nil {
+			o.logger.Warn("Can not remove buffered batch: " + err.Error())
+			return
+		}
+		files = files[1:]
+	}
+}
+
+// bufferedBatches returns all batches within the buffer directory ordered by their age.
+func (o *influxOutput) bufferedBatches() []string {
+	if o.config.InfluxDB.BufferDir == "" {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(o.config.InfluxDB.BufferDir, "*"+influxBufferExtension))
+	if err != nil {
+		o.logger.Warn("Can not list buffered batches: " + err.Error())
+		return nil
+	}
+	slices.Sort(files)
+	return files
+}
+
+// countLines returns the number of values within the batch.
+func countLines(content []byte) float64 {
+	return float64(bytes.Count(content, []byte("\n")))
+}
+
+// toLine converts the snapshot into the line protocol. The metric name is used as measurement, the labels, the
+// installation and the physical address as tags.
+func (o *influxOutput) toLine(snapshot *Snapshot) string {
+	tags := map[string]string{"physicalAddress": snapshot.source.String()}
+	for name, value := range snapshot.config.Labels {
+		tags[name] = value
+	}
+	if o.config.Installation != "" {
+		tags["installation"] = o.config.Installation
+	}
+	var names []string
+	for name := range tags {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	line := strings.Builder{}
+	line.WriteString(escapeInflux(snapshot.name, ", "))
+	for _, name := range names {
+		if tags[name] == "" {
+			continue
+		}
+		line.WriteString("," + escapeInflux(name, ",= ") + "=" + escapeInflux(tags[name], ",= "))
+	}
+	line.WriteString(" value=" + strconv.FormatFloat(snapshot.value, 'f', -1, 64))
+	line.WriteString(" " + strconv.FormatInt(snapshot.timestamp.UnixNano(), 10))
+	return line.String()
+}
+
+// escapeInflux escapes the given special characters and backslashes within measurements, tag keys and tag values.
+func escapeInflux(str string, special string) string {
+	builder := strings.Builder{}
+	for _, c := range str {
+		if c == '\\' || strings.ContainsRune(special, c) {
+			builder.WriteRune('\\')
+		}
+		builder.WriteRune(c)
+	}
+	return builder.String()
+}
@@ -0,0 +1,246 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_influxWriteURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *InfluxDBConfig
+		want    string
+		wantErr bool
+	}{
+		{"v2", &InfluxDBConfig{URL: "http://localhost:8086", Org: "home", Bucket: "knx"}, "http://localhost:8086/api/v2/write?bucket=knx&org=home&precision=ns", false},
+		{"v1", &InfluxDBConfig{URL: "http://localhost:8086/", Version: 1, Database: "knx", RetentionPolicy: "1y"}, "http://localhost:8086/write?db=knx&precision=ns&rp=1y", false},
+		{"v2 without bucket", &InfluxDBConfig{URL: "http://localhost:8086"}, "", true},
+		{"v1 without database", &InfluxDBConfig{URL: "http://localhost:8086", Version: 1}, "", true},
+		{"invalid version", &InfluxDBConfig{URL: "http://localhost:8086", Version: 3, Bucket: "knx"}, "", true},
+		{"invalid url", &InfluxDBConfig{URL: "localhost", Bucket: "knx"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := influxWriteURL(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("influxWriteURL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInfluxOutput_toLine(t *testing.T) {
+	timestamp := time.Unix(1700000000, 123)
+	tests := []struct {
+		name         string
+		installation string
+		snapshot     *Snapshot
+		want         string
+	}{
+		{
+			"simple",
+			"",
+			&Snapshot{name: "knx_temperature", source: PhysicalAddress(0x1105), value: 21.5, timestamp: timestamp, config: &GroupAddressConfig{}},
+			"knx_temperature,physicalAddress=1.1.5 value=21.5 1700000000000000123",
+		},
+		{
+			"labels and installation",
+			"home",
+			&Snapshot{name: "knx_temperature", source: PhysicalAddress(0x1105), value: 21, timestamp: timestamp, config: &GroupAddressConfig{Labels: map[string]string{"room": "Living room", "floor": "a=b,c", "empty": ""}}},
+			`knx_temperature,floor=a\=b\,c,installation=home,physicalAddress=1.1.5,room=Living\ room value=21 1700000000000000123`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &influxOutput{config: &Config{Installation: tt.installation}}
+			assert.Equal(t, tt.want, output.toLine(tt.snapshot))
+		})
+	}
+}
+
+func TestInfluxOutput_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     InfluxDBConfig
+		wantPath   string
+		wantHeader string
+	}{
+		{"v2", InfluxDBConfig{Org: "home", Bucket: "knx", Token: "secret"}, "/api/v2/write", "Token secret"},
+		{"v1", InfluxDBConfig{Version: 1, Database: "knx", Username: "user", Password: "pass"}, "/write", "Basic dXNlcjpwYXNz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			requests := make(chan *http.Request, 1)
+			bodies := make(chan string, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests <- r
+				bodies <- string(body)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			tt.config.URL = server.URL
+			tt.config.BatchSize = 2
+			output, err := NewInfluxDBOutput(&Config{InfluxDB: &tt.config})
+			assert.NoError(t, err)
+			go output.Run(ctx)
+
+			config := &GroupAddressConfig{Export: true}
+			output.Publish(&Snapshot{name: "knx_a", source: 1, value: 1, timestamp: time.Unix(1, 0), config: config})
+			output.Publish(&Snapshot{name: "knx_b", source: 1, value: 2, timestamp: time.Unix(2, 0), config: &GroupAddressConfig{}})
+			output.Publish(&Snapshot{name: "knx_c", source: 1, value: math.NaN(), timestamp: time.Unix(3, 0), config: config})
+			output.Publish(&Snapshot{name: "knx_d", source: 1, value: 4, timestamp: time.Unix(4, 0), config: config})
+
+			select {
+			case request := <-requests:
+				assert.Equal(t, tt.wantPath, request.URL.Path)
+				assert.Equal(t, "ns", request.URL.Query().Get("precision"))
+				assert.Equal(t, tt.wantHeader, request.Header.Get("Authorization"))
+				assert.Equal(t, "knx_a,physicalAddress=0.0.1 value=1 1000000000\nknx_d,physicalAddress=0.0.1 value=4 4000000000\n", <-bodies)
+			case <-time.After(5 * time.Second):
+				t.Fatal("no write request received")
+			}
+		})
+	}
+}
+
+func TestInfluxOutput_flush(t *testing.T) {
+	var lock sync.Mutex
+	var status int
+	var received []string
+	setStatus := func(s int) {
+		lock.Lock()
+		defer lock.Unlock()
+		status = s
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if status == http.StatusNoContent {
+			body, _ := io.ReadAll(r.Body)
+			received = append(received, string(body))
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	bufferDir := filepath.Join(t.TempDir(), "buffer")
+	retries := uint(1)
+	output, err := NewInfluxDBOutput(&Config{InfluxDB: &InfluxDBConfig{
+		URL:                server.URL,
+		Bucket:             "knx",
+		MaxRetries:         &retries,
+		BufferDir:          bufferDir,
+		MaxBufferedBatches: 2,
+	}})
+	assert.NoError(t, err)
+	influx := output.(*influxOutput)
+	influx.retryInterval = time.Millisecond
+
+	// A rejected batch is dropped instead of buffered.
+	setStatus(http.StatusBadRequest)
+	influx.flush(context.Background(), []byte("invalid\n"))
+	files, err := os.ReadDir(bufferDir)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	// The unreachable InfluxDB leads to buffered batches. Only the two newest ones are kept.
+	setStatus(http.StatusServiceUnavailable)
+	for _, line := range []string{"a value=1 1", "b value=2 2", "c value=3 3"} {
+		influx.flush(context.Background(), []byte(line+"\n"))
+	}
+	files, err = os.ReadDir(bufferDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, 2.0, testutil.ToFloat64(influx.dropped))
+
+	// After the InfluxDB is reachable again, all buffered batches are written before the new one.
+	setStatus(http.StatusNoContent)
+	influx.flush(context.Background(), []byte("d value=4 4\n"))
+
+	assert.Equal(t, []string{"b value=2 2\n", "c value=3 3\n", "d value=4 4\n"}, received)
+	files, err = os.ReadDir(bufferDir)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestInfluxOutput_RunWhileWriting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := make(chan struct{}, 10)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	output, err := NewInfluxDBOutput(&Config{InfluxDB: &InfluxDBConfig{URL: server.URL, Bucket: "knx", BatchSize: 1}})
+	assert.NoError(t, err)
+	influx := output.(*influxOutput)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		output.Run(ctx)
+	}()
+
+	config := &GroupAddressConfig{Export: true}
+	publish := func(value float64) {
+		output.Publish(&Snapshot{name: "knx_a", source: 1, value: value, timestamp: time.Unix(1, 0), config: config})
+		assert.Eventually(t, func() bool { return len(influx.snapshots) == 0 }, 5*time.Second, time.Millisecond)
+	}
+
+	// The first batch blocks the writer, the following ones wait for it until there are too many of them.
+	publish(0)
+	select {
+	case <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no write request received")
+	}
+	for i := 1; i <= influxPendingBatches+3; i++ {
+		publish(float64(i))
+	}
+	assert.Eventually(t, func() bool { return testutil.ToFloat64(influx.dropped) == 3 }, 5*time.Second, time.Millisecond)
+
+	cancel()
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("output is still running")
+	}
+	assert.Len(t, requests, influxPendingBatches)
+}
@@ -20,6 +20,9 @@ import (
 	"log/slog"
 	"math"
 	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -27,36 +30,61 @@ import (
 	"github.com/vapourismo/knx-go/knx/dpt"
 )
 
+// maxWarnings is the number of recent warnings which are kept by the listener.
+const maxWarnings = 50
+
 type Listener interface {
 	Run(ctx context.Context, inbound <-chan knx.GroupEvent)
 	IsActive() bool
+	// Warnings returns the recent warnings while processing the received telegrams. The newest warning is the first.
+	Warnings() []Warning
+	// LastTelegram returns the time when the latest telegram was received. It is zero if no telegram was received yet.
+	LastTelegram() time.Time
+}
+
+// Warning describes a received telegram which could not be processed.
+type Warning struct {
+	Time        time.Time       `json:"time"`
+	Source      PhysicalAddress `json:"source"`
+	Destination GroupAddress    `json:"destination"`
+	Message     string          `json:"message"`
 }
 
 type listener struct {
 	config         *Config
-	metricsChan    chan *Snapshot
+	queue          SnapshotQueue
 	messageCounter *prometheus.CounterVec
-	active         bool
+	active         atomic.Bool
 	logger         *slog.Logger
+	lock           sync.RWMutex
+	warnings       []Warning
+	lastTelegram   time.Time
+	telegrams      *telegramBroadcaster
 }
 
-func NewListener(config *Config, metricsChan chan *Snapshot, messageCounter *prometheus.CounterVec) Listener {
-	return &listener{
+func NewListener(config *Config, queue SnapshotQueue, messageCounter *prometheus.CounterVec, telegrams *telegramBroadcaster) Listener {
+	logger := slog.With(
+		"connectionType", config.Connection.Type,
+		"endpoint", config.Connection.Endpoint,
+	)
+	if config.Installation != "" {
+		logger = logger.With("installation", config.Installation)
+	}
+	l := &listener{
 		config:         config,
-		metricsChan:    metricsChan,
+		queue:          queue,
 		messageCounter: messageCounter,
-		active:         true,
-		logger: slog.With(
-			"connectionType", config.Connection.Type,
-			"endpoint", config.Connection.Endpoint,
-		),
+		logger:         logger,
+		telegrams:      telegrams,
 	}
+	l.active.Store(true)
+	return l
 }
 
 func (l *listener) Run(ctx context.Context, inbound <-chan knx.GroupEvent) {
 	l.logger.Info("Waiting for incoming knx telegrams...")
 	defer func() {
-		l.active = false
+		l.active.Store(false)
 		l.logger.Warn("Finished listening for incoming knx telegrams")
 	}()
 loop:
@@ -74,23 +102,37 @@ loop:
 }
 
 func (l *listener) IsActive() bool {
-	return l.active
+	return l.active.Load()
+}
+
+func (l *listener) LastTelegram() time.Time {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.lastTelegram
 }
 
 func (l *listener) handleEvent(ctx context.Context, event knx.GroupEvent) {
 	l.messageCounter.WithLabelValues("received", "false").Inc()
+	l.lock.Lock()
+	l.lastTelegram = time.Now()
+	l.lock.Unlock()
 	destination := GroupAddress(event.Destination)
 	logger := l.logger.With(
 		"command", event.Command.String(),
 		"source", event.Source.String(),
 		"destination", event.Destination.String(),
 	)
+	telegram := newTelegramEvent(l.config.Installation, event)
+	defer l.telegrams.Publish(telegram)
 
 	addr, ok := l.config.AddressConfigs[destination]
 	if !ok {
 		logger.Debug("Received event but ignore them due to missing configuration")
 		return
 	}
+	metricName := l.config.NameFor(addr)
+	telegram.Name = metricName
+	telegram.DPT = addr.DPT
 
 	if event.Command == knx.GroupRead {
 		logger.Debug("Skip group event as it is a GroupRead message.")
@@ -101,31 +143,54 @@ func (l *listener) handleEvent(ctx context.Context, event knx.GroupEvent) {
 	logger = logger.With("dpt", addr.DPT)
 
 	if err != nil {
-		logger.Warn(err.Error())
+		l.warn(logger, event, err)
 		return
 	}
 
 	floatValue, err := extractAsFloat64(value)
 	if err != nil {
-		logger.Warn(err.Error())
+		l.warn(logger, event, err)
 		return
 	}
-	metricName := l.config.NameFor(addr)
+	telegram.Value = &floatValue
+	telegram.Text = value.String()
+	telegram.Unit = value.Unit()
 	logger.With(
 		"metricName", metricName,
 		"value", value,
 	).Log(ctx, slog.LevelDebug-2, "Processed received group address value")
-	l.metricsChan <- &Snapshot{
+	l.queue.Push(ctx, &Snapshot{
 		name:        metricName,
 		value:       floatValue,
 		source:      PhysicalAddress(event.Source),
 		timestamp:   time.Now(),
 		config:      addr,
 		destination: destination,
-	}
+	})
 	l.messageCounter.WithLabelValues("received", "true").Inc()
 }
 
+func (l *listener) Warnings() []Warning {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return slices.Clone(l.warnings)
+}
+
+// warn logs the error and keeps it within the recent warnings.
+func (l *listener) warn(logger *slog.Logger, event knx.GroupEvent, err error) {
+	logger.Warn(err.Error())
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	warning := Warning{
+		Time:        time.Now(),
+		Source:      PhysicalAddress(event.Source),
+		Destination: GroupAddress(event.Destination),
+		Message:     err.Error(),
+	}
+	l.warnings = append([]Warning{warning}, l.warnings[:min(len(l.warnings), maxWarnings-1)]...)
+}
+
 func unpackEvent(event knx.GroupEvent, addr *GroupAddressConfig) (DPT, error) {
 	v, found := dpt.Produce(addr.DPT)
 	if !found {
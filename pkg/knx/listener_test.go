@@ -131,8 +131,9 @@ func Test_listener_Run(t *testing.T) {
 						GroupAddress(7): {Export: false},
 					},
 				},
-				metricsChan,
+				NewSnapshotQueue(metricsChan, Block),
 				prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"direction", "processed"}),
+				nil,
 			)
 
 			go l.Run(ctx, inbound)
@@ -152,3 +153,23 @@ func Test_listener_Run(t *testing.T) {
 		})
 	}
 }
+
+func Test_listener_Warnings(t *testing.T) {
+	l := NewListener(
+		&Config{AddressConfigs: GroupAddressConfigSet{GroupAddress(2): {Name: "b", DPT: "5.001", Export: true}}},
+		nil,
+		prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"direction", "processed"}),
+		nil,
+	)
+	for i := 0; i < maxWarnings+1; i++ {
+		l.(*listener).handleEvent(context.Background(), knx.GroupEvent{
+			Source: cemi.IndividualAddr(i), Destination: cemi.GroupAddr(2), Command: knx.GroupWrite, Data: []byte{0},
+		})
+	}
+
+	warnings := l.Warnings()
+	assert.Len(t, warnings, maxWarnings)
+	assert.Equal(t, PhysicalAddress(maxWarnings), warnings[0].Source)
+	assert.Equal(t, GroupAddress(2), warnings[0].Destination)
+	assert.Contains(t, warnings[0].Message, "can not unpack data")
+}
@@ -0,0 +1,182 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// VanishedPolicy defines how group addresses are handled while merging which are not part of the export anymore.
+type VanishedPolicy string
+
+// FlagVanished keeps vanished group addresses but marks them as Vanished.
+const FlagVanished = VanishedPolicy("flag")
+
+// RemoveVanished removes vanished group addresses from the configuration.
+const RemoveVanished = VanishedPolicy("remove")
+
+// ConvertSummary contains all changes that were made to the group address configurations while converting.
+type ConvertSummary struct {
+	// Added contains all group addresses that were added to the configuration.
+	Added []GroupAddress
+	// Updated contains the changed fields for all group addresses that were updated from the export.
+	Updated map[GroupAddress][]string
+	// Vanished contains all group addresses that are not part of the export anymore but are still configured.
+	Vanished []GroupAddress
+	// Removed contains all group addresses that were removed because they are not part of the export anymore.
+	Removed []GroupAddress
+	// Collisions contains all metric name collisions of the converted group addresses.
+	Collisions []Collision
+}
+
+// String returns a short human-readable summary of all changes.
+func (s ConvertSummary) String() string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "%d added, %d updated, %d vanished, %d removed, %d collisions\n", len(s.Added), len(s.Updated), len(s.Vanished), len(s.Removed), len(s.Collisions))
+	for _, ga := range sortedAddresses(s.Added) {
+		_, _ = fmt.Fprintf(&sb, "+ %s\n", ga)
+	}
+	var updated []GroupAddress
+	for ga := range s.Updated {
+		updated = append(updated, ga)
+	}
+	for _, ga := range sortedAddresses(updated) {
+		_, _ = fmt.Fprintf(&sb, "~ %s: %s\n", ga, strings.Join(s.Updated[ga], ", "))
+	}
+	for _, ga := range sortedAddresses(s.Vanished) {
+		_, _ = fmt.Fprintf(&sb, "? %s\n", ga)
+	}
+	for _, ga := range sortedAddresses(s.Removed) {
+		_, _ = fmt.Fprintf(&sb, "- %s\n", ga)
+	}
+	for _, c := range s.Collisions {
+		_, _ = fmt.Fprintf(&sb, "! %s\n", c)
+	}
+	return sb.String()
+}
+
+func sortedAddresses(addresses []GroupAddress) []GroupAddress {
+	sorted := slices.Clone(addresses)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// mergeIntoConfig merges the converted group address configurations into the existing configuration file. Everything
+// except the Name, Comment and DPT of already configured group addresses will be kept. Group addresses which were
+// filtered out are kept as they are.
+func mergeIntoConfig(converted GroupAddressConfigSet, target string, policy VanishedPolicy, filtered map[GroupAddress]bool) (map[string]interface{}, ConvertSummary, error) {
+	existing, err := readRawConfig(target)
+	if err != nil {
+		return nil, ConvertSummary{}, err
+	}
+	if _, ok := existing["Installations"]; ok {
+		return nil, ConvertSummary{}, fmt.Errorf("can not merge into %s as it defines multiple installations", target)
+	}
+
+	addressConfigs := make(GroupAddressConfigSet)
+	if raw, ok := existing["AddressConfigs"]; ok {
+		content, err := json.Marshal(raw)
+		if err != nil {
+			return nil, ConvertSummary{}, err
+		}
+		if err = json.Unmarshal(content, &addressConfigs); err != nil {
+			return nil, ConvertSummary{}, fmt.Errorf("can not read address configs from %s: %s", target, err)
+		}
+	}
+
+	summary := mergeAddressConfigs(addressConfigs, converted, policy, filtered)
+	existing["AddressConfigs"] = addressConfigs
+	if _, ok := existing["MetricsPrefix"]; !ok {
+		existing["MetricsPrefix"] = "knx_"
+	}
+	return existing, summary, nil
+}
+
+// readRawConfig reads the configuration file without interpreting anything else than the AddressConfigs. This ensures
+// that all other settings are written back exactly as they were. It returns an empty configuration if the file does
+// not exist.
+func readRawConfig(target string) (map[string]interface{}, error) {
+	raw := make(map[string]interface{})
+	content, err := os.ReadFile(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return raw, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("can not read existing configuration %s: %s", target, err)
+	}
+	if err = yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("can not parse existing configuration %s: %s", target, err)
+	}
+	if raw == nil {
+		raw = make(map[string]interface{})
+	}
+	return raw, nil
+}
+
+func mergeAddressConfigs(existing GroupAddressConfigSet, converted GroupAddressConfigSet, policy VanishedPolicy, filtered map[GroupAddress]bool) ConvertSummary {
+	summary := ConvertSummary{Updated: make(map[GroupAddress][]string)}
+	for address, cfg := range converted {
+		current, ok := existing[address]
+		if !ok {
+			existing[address] = cfg
+			summary.Added = append(summary.Added, address)
+			continue
+		}
+
+		var changes []string
+		if cfg.Name != "" && current.Name != cfg.Name {
+			changes = append(changes, fmt.Sprintf("Name \"%s\" -> \"%s\"", current.Name, cfg.Name))
+			current.Name = cfg.Name
+		}
+		if current.Comment != cfg.Comment {
+			changes = append(changes, "Comment")
+			current.Comment = cfg.Comment
+		}
+		if cfg.DPT != "" && current.DPT != cfg.DPT {
+			changes = append(changes, fmt.Sprintf("DPT \"%s\" -> \"%s\"", current.DPT, cfg.DPT))
+			current.DPT = cfg.DPT
+		}
+		if current.Vanished {
+			changes = append(changes, "reappeared")
+			current.Vanished = false
+		}
+		if len(changes) > 0 {
+			summary.Updated[address] = changes
+		}
+	}
+
+	for address, cfg := range existing {
+		if _, ok := converted[address]; ok || filtered[address] {
+			continue
+		}
+		if policy == RemoveVanished {
+			delete(existing, address)
+			summary.Removed = append(summary.Removed, address)
+			continue
+		}
+		if !cfg.Vanished {
+			cfg.Vanished = true
+			summary.Vanished = append(summary.Vanished, address)
+		}
+	}
+	return summary
+}
@@ -0,0 +1,160 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertGroupAddresses_Merge(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "")
+	assert.NoError(t, err)
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+	existing, err := os.ReadFile("fixtures/merge-config.yaml")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(tmpFile.Name(), existing, 0600))
+
+	summary, err := ConvertGroupAddresses("export/fixtures/ga-export-1-1.csv", tmpFile.Name(), ConvertOptions{Merge: true, Vanished: FlagVanished})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []GroupAddress{GroupAddress(2049)}, summary.Added)
+	assert.Equal(t, map[GroupAddress][]string{
+		GroupAddress(1): {"Name \"old_temperature\" -> \"Temperatur_Kueche\"", "Comment"},
+	}, summary.Updated)
+	assert.Equal(t, []GroupAddress{GroupAddress(0x2d05)}, summary.Vanished)
+	assert.Empty(t, summary.Removed)
+
+	expected, err := os.ReadFile("fixtures/merge-config-merged.yaml")
+	assert.NoError(t, err)
+	expected, err = yaml.YAMLToJSON(expected)
+	assert.NoError(t, err)
+	actual, err := os.ReadFile(tmpFile.Name())
+	assert.NoError(t, err)
+	actual, err = yaml.YAMLToJSON(actual)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestConvertGroupAddresses_MergeNotExisting(t *testing.T) {
+	target := os.TempDir() + "/knx-exporter-merge-not-existing.yaml"
+	defer func() {
+		_ = os.Remove(target)
+	}()
+
+	summary, err := ConvertGroupAddresses("export/fixtures/ga-export-1-1.csv", target, ConvertOptions{Merge: true})
+	assert.NoError(t, err)
+	assert.Len(t, summary.Added, 3)
+
+	config, err := ReadConfig(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "knx_", config.MetricsPrefix)
+	assert.Len(t, config.AddressConfigs, 3)
+}
+
+func Test_mergeAddressConfigs(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  GroupAddressConfigSet
+		converted GroupAddressConfigSet
+		policy    VanishedPolicy
+		filtered  map[GroupAddress]bool
+		want      GroupAddressConfigSet
+		summary   ConvertSummary
+	}{
+		{
+			"keep user settings",
+			GroupAddressConfigSet{1: {Name: "a", DPT: "1.001", Export: true, MetricType: "gauge", Labels: map[string]string{"room": "office"}}},
+			GroupAddressConfigSet{1: {Name: "b", DPT: "1.002"}},
+			FlagVanished,
+			nil,
+			GroupAddressConfigSet{1: {Name: "b", DPT: "1.002", Export: true, MetricType: "gauge", Labels: map[string]string{"room": "office"}}},
+			ConvertSummary{Updated: map[GroupAddress][]string{1: {"Name \"a\" -> \"b\"", "DPT \"1.001\" -> \"1.002\""}}},
+		},
+		{
+			"keep dpt if unknown",
+			GroupAddressConfigSet{1: {Name: "a", DPT: "1.001"}},
+			GroupAddressConfigSet{1: {Name: "a", DPT: ""}},
+			FlagVanished,
+			nil,
+			GroupAddressConfigSet{1: {Name: "a", DPT: "1.001"}},
+			ConvertSummary{Updated: map[GroupAddress][]string{}},
+		},
+		{
+			"flag vanished",
+			GroupAddressConfigSet{1: {Name: "a"}},
+			GroupAddressConfigSet{},
+			FlagVanished,
+			nil,
+			GroupAddressConfigSet{1: {Name: "a", Vanished: true}},
+			ConvertSummary{Updated: map[GroupAddress][]string{}, Vanished: []GroupAddress{1}},
+		},
+		{
+			"remove vanished",
+			GroupAddressConfigSet{1: {Name: "a"}},
+			GroupAddressConfigSet{},
+			RemoveVanished,
+			nil,
+			GroupAddressConfigSet{},
+			ConvertSummary{Updated: map[GroupAddress][]string{}, Removed: []GroupAddress{1}},
+		},
+		{
+			"keep filtered",
+			GroupAddressConfigSet{1: {Name: "a", Export: true}},
+			GroupAddressConfigSet{},
+			RemoveVanished,
+			map[GroupAddress]bool{1: true},
+			GroupAddressConfigSet{1: {Name: "a", Export: true}},
+			ConvertSummary{Updated: map[GroupAddress][]string{}},
+		},
+		{
+			"reappeared",
+			GroupAddressConfigSet{1: {Name: "a", Vanished: true}},
+			GroupAddressConfigSet{1: {Name: "a"}},
+			FlagVanished,
+			nil,
+			GroupAddressConfigSet{1: {Name: "a"}},
+			ConvertSummary{Updated: map[GroupAddress][]string{1: {"reappeared"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := mergeAddressConfigs(tt.existing, tt.converted, tt.policy, tt.filtered)
+			assert.Equal(t, tt.want, tt.existing)
+			assert.Equal(t, tt.summary, summary)
+		})
+	}
+}
+
+func TestConvertSummary_String(t *testing.T) {
+	summary := ConvertSummary{
+		Added:    []GroupAddress{2, 1},
+		Updated:  map[GroupAddress][]string{3: {"Comment"}},
+		Vanished: []GroupAddress{4},
+		Removed:  []GroupAddress{5},
+	}
+	assert.Equal(t, `2 added, 1 updated, 1 vanished, 1 removed, 0 collisions
++ 0/0/1
++ 0/0/2
+~ 0/0/3: Comment
+? 0/0/4
+- 0/0/5
+`, summary.String())
+}
@@ -0,0 +1,213 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/vapourismo/knx-go/knx/dpt"
+)
+
+const defaultMQTTBufferSize = 256
+const mqttPublishTimeout = 5 * time.Second
+
+// mqttMessage is the payload which is published for every received value.
+type mqttMessage struct {
+	Name        string    `json:"name"`
+	Value       float64   `json:"value"`
+	Unit        string    `json:"unit,omitempty"`
+	Source      string    `json:"source"`
+	Destination string    `json:"destination"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+type mqttOutput struct {
+	config      *Config
+	clientID    string
+	topicPrefix string
+	client      mqtt.Client
+	snapshots   chan *Snapshot
+	connected   chan bool
+	active      atomic.Bool
+	logger      *slog.Logger
+}
+
+// NewMQTTOutput creates a SnapshotOutput which publishes the values of all exported group addresses to the MQTT broker
+// defined within the configuration.
+func NewMQTTOutput(config *Config) (SnapshotOutput, error) {
+	mqttConfig := config.MQTT
+	logger := slog.With("broker", mqttConfig.Broker)
+	clientID := mqttConfig.ClientID
+	if clientID == "" {
+		clientID = "knx-exporter"
+		if config.Installation != "" {
+			clientID += "-" + config.Installation
+		}
+	}
+	topicPrefix := mqttConfig.TopicPrefix
+	if topicPrefix == "" {
+		topicPrefix = "knx"
+		if config.Installation != "" {
+			topicPrefix += "/" + config.Installation
+		}
+	}
+	if config.Installation != "" {
+		logger = logger.With("installation", config.Installation)
+	}
+	if mqttConfig.QoS > 2 {
+		return nil, fmt.Errorf("invalid mqtt qos %d: must be 0, 1 or 2", mqttConfig.QoS)
+	}
+	bufferSize := mqttConfig.BufferSize
+	if bufferSize == 0 {
+		bufferSize = defaultMQTTBufferSize
+	}
+
+	output := &mqttOutput{
+		config:      config,
+		clientID:    clientID,
+		topicPrefix: topicPrefix,
+		snapshots:   make(chan *Snapshot, bufferSize),
+		connected:   make(chan bool, 1),
+		logger:      logger,
+	}
+	output.active.Store(true)
+	options := mqtt.NewClientOptions().
+		AddBroker(mqttConfig.Broker).
+		SetClientID(clientID).
+		SetUsername(mqttConfig.Username).
+		SetPassword(mqttConfig.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOnConnectHandler(func(mqtt.Client) {
+			logger.Info("Connected to mqtt broker")
+			select {
+			case output.connected <- true:
+			default:
+			}
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.Warn("Lost connection to mqtt broker: " + err.Error())
+		})
+	if mqttConfig.TLS != nil {
+		tlsConfig, err := mqttConfig.TLS.toTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid mqtt tls config: %s", err)
+		}
+		options.SetTLSConfig(tlsConfig)
+	}
+
+	output.client = mqtt.NewClient(options)
+	return output, nil
+}
+
+func (o *mqttOutput) Publish(snapshot *Snapshot) {
+	if !snapshot.config.Export {
+		return
+	}
+	select {
+	case o.snapshots <- snapshot:
+	default:
+		o.logger.Debug("Drop value as the mqtt buffer is full", "destination", snapshot.destination)
+	}
+}
+
+func (o *mqttOutput) Run(ctx context.Context) {
+	o.active.Store(true)
+	defer func() { o.active.Store(false) }()
+
+	o.logger.Info("Connecting to mqtt broker")
+	defer o.client.Disconnect(250)
+	// The client retries to connect until it succeeds. All snapshots are buffered meanwhile.
+	select {
+	case <-o.client.Connect().Done():
+	case <-ctx.Done():
+		return
+	}
+
+	for {
+		select {
+		case snapshot := <-o.snapshots:
+			o.publish(snapshot)
+		case <-o.connected:
+			// The discovery messages are published after every connect as the broker may have lost them.
+			if o.config.MQTT.HomeAssistantDiscovery {
+				o.publishDiscovery()
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (o *mqttOutput) IsActive() bool {
+	return o.active.Load()
+}
+
+func (o *mqttOutput) Name() string {
+	return "mqtt"
+}
+
+func (o *mqttOutput) publish(snapshot *Snapshot) {
+	payload, err := json.Marshal(newMQTTMessage(snapshot))
+	if err != nil {
+		o.logger.Warn("Can not marshal mqtt message: " + err.Error())
+		return
+	}
+	o.send(o.topicFor(snapshot), o.config.MQTT.Retain, payload)
+}
+
+// send publishes the payload to the topic and waits until it was sent.
+func (o *mqttOutput) send(topic string, retain bool, payload []byte) {
+	token := o.client.Publish(topic, o.config.MQTT.QoS, retain, payload)
+	if !token.WaitTimeout(mqttPublishTimeout) {
+		o.logger.Warn("Timeout while publishing value", "topic", topic)
+		return
+	}
+	if token.Error() != nil {
+		o.logger.Warn("Can not publish value: "+token.Error().Error(), "topic", topic)
+	}
+}
+
+// topicFor returns the MQTTTopic of the group address or the group address appended to the topic prefix.
+func (o *mqttOutput) topicFor(snapshot *Snapshot) string {
+	return o.topicForAddress(snapshot.destination, snapshot.config)
+}
+
+func (o *mqttOutput) topicForAddress(address GroupAddress, config *GroupAddressConfig) string {
+	if config.MQTTTopic != "" {
+		return config.MQTTTopic
+	}
+	return o.topicPrefix + "/" + address.String()
+}
+
+func newMQTTMessage(snapshot *Snapshot) mqttMessage {
+	message := mqttMessage{
+		Name:        snapshot.name,
+		Value:       snapshot.value,
+		Source:      snapshot.source.String(),
+		Destination: snapshot.destination.String(),
+		Timestamp:   snapshot.timestamp,
+	}
+	if datapoint, ok := dpt.Produce(snapshot.config.DPT); ok {
+		message.Unit = datapoint.Unit()
+	}
+	return message
+}
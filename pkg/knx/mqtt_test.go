@@ -0,0 +1,219 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	server "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMQTTOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		tls    bool
+		config MQTTConfig
+		topic  string
+	}{
+		{"plain", false, MQTTConfig{Retain: true}, "knx/0/0/1"},
+		{"tls", true, MQTTConfig{Retain: true, QoS: 1, TopicPrefix: "home"}, "home/0/0/1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			broker, tlsConfig := startBroker(t, tt.tls)
+			if tt.tls {
+				tt.config.Broker = "ssl://" + broker
+				tt.config.TLS = tlsConfig
+			} else {
+				tt.config.Broker = "tcp://" + broker
+			}
+
+			output, err := NewMQTTOutput(&Config{MQTT: &tt.config})
+			assert.NoError(t, err)
+			go output.Run(ctx)
+
+			timestamp := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			output.Publish(&Snapshot{name: "not_exported", destination: 2, config: &GroupAddressConfig{DPT: "9.001"}})
+			output.Publish(&Snapshot{
+				name:        "knx_temperature",
+				source:      PhysicalAddress(0x1105),
+				destination: 1,
+				value:       21.5,
+				timestamp:   timestamp,
+				config:      &GroupAddressConfig{DPT: "9.001", Export: true},
+			})
+
+			// The value is retained, so it is received even if the subscription is created afterward.
+			options := mqtt.NewClientOptions().AddBroker(tt.config.Broker).SetClientID("test-subscriber")
+			if tt.tls {
+				options.SetTLSConfig(mustTLSConfig(t, tlsConfig))
+			}
+			messages := make(chan mqtt.Message, 10)
+			subscriber := mqtt.NewClient(options)
+			token := subscriber.Connect()
+			assert.True(t, token.WaitTimeout(5*time.Second))
+			assert.NoError(t, token.Error())
+			defer subscriber.Disconnect(0)
+
+			// Subscribe until the retained value was published. Live messages are ignored as they are never retained.
+			received := assert.Eventually(t, func() bool {
+				subscriber.Subscribe("#", 1, func(_ mqtt.Client, message mqtt.Message) {
+					if !message.Retained() {
+						return
+					}
+					select {
+					case messages <- message:
+					default:
+					}
+				}).Wait()
+				time.Sleep(100 * time.Millisecond)
+				subscriber.Unsubscribe("#").Wait()
+				return len(messages) > 0
+			}, 5*time.Second, 10*time.Millisecond)
+			if !received {
+				return
+			}
+
+			message := <-messages
+			assert.Equal(t, tt.topic, message.Topic())
+			assert.True(t, message.Retained())
+			var payload mqttMessage
+			assert.NoError(t, json.Unmarshal(message.Payload(), &payload))
+			assert.Equal(t, mqttMessage{
+				Name:        "knx_temperature",
+				Value:       21.5,
+				Unit:        "°C",
+				Source:      "1.1.5",
+				Destination: "0/0/1",
+				Timestamp:   timestamp,
+			}, payload)
+		})
+	}
+}
+
+func TestNewMQTTOutput(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          *Config
+		wantClientID    string
+		wantTopicPrefix string
+		wantErr         bool
+	}{
+		{"defaults", &Config{MQTT: &MQTTConfig{Broker: "tcp://localhost:1883"}}, "knx-exporter", "knx", false},
+		{"installation", &Config{Installation: "home", MQTT: &MQTTConfig{Broker: "tcp://localhost:1883"}}, "knx-exporter-home", "knx/home", false},
+		{"custom", &Config{Installation: "home", MQTT: &MQTTConfig{Broker: "tcp://localhost:1883", ClientID: "exporter", TopicPrefix: "house"}}, "exporter", "house", false},
+		{"invalid qos", &Config{MQTT: &MQTTConfig{Broker: "tcp://localhost:1883", QoS: 3}}, "", "", true},
+		{"missing ca", &Config{MQTT: &MQTTConfig{Broker: "ssl://localhost:8883", TLS: &TLSConfig{CAFile: "fixtures/missing.pem"}}}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMQTTOutput(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewMQTTOutput() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			output := got.(*mqttOutput)
+			reader := output.client.OptionsReader()
+			assert.Equal(t, tt.wantClientID, reader.ClientID())
+			assert.Equal(t, tt.wantTopicPrefix, output.topicPrefix)
+		})
+	}
+}
+
+func TestMQTTOutput_topicFor(t *testing.T) {
+	output := &mqttOutput{topicPrefix: "knx"}
+	assert.Equal(t, "knx/1/2/3", output.topicFor(&Snapshot{destination: GroupAddress(0x0a03), config: &GroupAddressConfig{}}))
+	assert.Equal(t, "home/kitchen/temperature", output.topicFor(&Snapshot{destination: GroupAddress(0x0a03), config: &GroupAddressConfig{MQTTTopic: "home/kitchen/temperature"}}))
+}
+
+// startBroker starts an in-process MQTT broker and returns its address. If withTLS is set, it also returns the TLSConfig
+// to connect to it.
+func startBroker(t *testing.T, withTLS bool) (string, *TLSConfig) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	var tlsConfig *TLSConfig
+	if withTLS {
+		var serverConfig *tls.Config
+		tlsConfig, serverConfig = createCertificates(t)
+		listener = tls.NewListener(listener, serverConfig)
+	}
+
+	broker := server.New(&server.Options{})
+	assert.NoError(t, broker.AddHook(new(auth.AllowHook), nil))
+	assert.NoError(t, broker.AddListener(listeners.NewNet("test", listener)))
+	assert.NoError(t, broker.Serve())
+	t.Cleanup(func() {
+		_ = broker.Close()
+	})
+	return listener.Addr().String(), tlsConfig
+}
+
+// createCertificates creates a self-signed server certificate for 127.0.0.1 and stores it as CAFile for the client.
+func createCertificates(t *testing.T) (*TLSConfig, *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+
+	return &TLSConfig{CAFile: caFile}, &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+func mustTLSConfig(t *testing.T, config *TLSConfig) *tls.Config {
+	tlsConfig, err := config.toTLSConfig()
+	assert.NoError(t, err)
+	return tlsConfig
+}
@@ -0,0 +1,299 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/vapourismo/knx-go/knx/dpt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc/credentials"
+)
+
+const defaultOTLPInterval = 60 * time.Second
+const defaultOTLPTimeout = 10 * time.Second
+const otlpBufferSize = 256
+
+// otlpScope is the instrumentation scope of all exported metrics.
+var otlpScope = instrumentation.Scope{Name: "github.com/chr-fritz/knx-exporter"}
+
+type otlpOutput struct {
+	config    *Config
+	exporter  sdkmetric.Exporter
+	resource  *resource.Resource
+	snapshots chan *Snapshot
+	// latest contains the latest snapshot of every group address and device which is exported on every interval.
+	latest map[SnapshotKey]*Snapshot
+	// startTimes contains the time when a value was received the first time. It is the start time of the sums.
+	startTimes map[SnapshotKey]time.Time
+	interval   time.Duration
+	timeout    time.Duration
+	active     atomic.Bool
+	logger     *slog.Logger
+}
+
+// NewOTLPOutput creates a SnapshotOutput which periodically exports the latest values of all exported group addresses
+// as OpenTelemetry metrics to the collector defined within the configuration.
+func NewOTLPOutput(config *Config) (SnapshotOutput, error) {
+	otlpConfig := config.OTLP
+	timeout := time.Duration(otlpConfig.Timeout)
+	if timeout == 0 {
+		timeout = defaultOTLPTimeout
+	}
+	exporter, err := newOTLPExporter(otlpConfig, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := []attribute.KeyValue{
+		attribute.String("service.name", "knx-exporter"),
+		attribute.String("knx.gateway.endpoint", config.Connection.Endpoint),
+	}
+	logger := slog.With("endpoint", otlpConfig.Endpoint)
+	if config.Installation != "" {
+		attributes = append(attributes, attribute.String("knx.installation", config.Installation))
+		logger = logger.With("installation", config.Installation)
+	}
+
+	output := &otlpOutput{
+		config:     config,
+		exporter:   exporter,
+		resource:   resource.NewSchemaless(attributes...),
+		snapshots:  make(chan *Snapshot, otlpBufferSize),
+		latest:     make(map[SnapshotKey]*Snapshot),
+		startTimes: make(map[SnapshotKey]time.Time),
+		interval:   time.Duration(otlpConfig.Interval),
+		timeout:    timeout,
+		logger:     logger,
+	}
+	output.active.Store(true)
+	if output.interval == 0 {
+		output.interval = defaultOTLPInterval
+	}
+	return output, nil
+}
+
+// newOTLPExporter creates the grpc or http exporter. Both of them connect lazily to the collector.
+func newOTLPExporter(config *OTLPConfig, timeout time.Duration) (sdkmetric.Exporter, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("otlp endpoint is required")
+	}
+	var tlsConfig = &TLSConfig{}
+	if config.TLS != nil {
+		tlsConfig = config.TLS
+	}
+
+	switch config.Protocol {
+	case "", OTLPGRPC:
+		options := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpoint(config.Endpoint),
+			otlpmetricgrpc.WithTimeout(timeout),
+			otlpmetricgrpc.WithHeaders(config.Headers),
+		}
+		if config.Insecure {
+			options = append(options, otlpmetricgrpc.WithInsecure())
+		} else {
+			clientConfig, err := tlsConfig.toTLSConfig()
+			if err != nil {
+				return nil, fmt.Errorf("invalid otlp tls config: %s", err)
+			}
+			options = append(options, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(clientConfig)))
+		}
+		exporter, err := otlpmetricgrpc.New(context.Background(), options...)
+		if err != nil {
+			return nil, fmt.Errorf("can not create otlp grpc exporter: %s", err)
+		}
+		return exporter, nil
+	case OTLPHTTP:
+		options := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpoint(config.Endpoint),
+			otlpmetrichttp.WithTimeout(timeout),
+			otlpmetrichttp.WithHeaders(config.Headers),
+		}
+		if config.URLPath != "" {
+			options = append(options, otlpmetrichttp.WithURLPath(config.URLPath))
+		}
+		if config.Insecure {
+			options = append(options, otlpmetrichttp.WithInsecure())
+		} else {
+			clientConfig, err := tlsConfig.toTLSConfig()
+			if err != nil {
+				return nil, fmt.Errorf("invalid otlp tls config: %s", err)
+			}
+			options = append(options, otlpmetrichttp.WithTLSClientConfig(clientConfig))
+		}
+		exporter, err := otlpmetrichttp.New(context.Background(), options...)
+		if err != nil {
+			return nil, fmt.Errorf("can not create otlp http exporter: %s", err)
+		}
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("invalid otlp protocol given: \"%s\"", config.Protocol)
+	}
+}
+
+func (o *otlpOutput) Publish(snapshot *Snapshot) {
+	if !snapshot.config.Export {
+		return
+	}
+	select {
+	case o.snapshots <- snapshot:
+	default:
+		o.logger.Debug("Drop value as the otlp buffer is full", "destination", snapshot.destination)
+	}
+}
+
+func (o *otlpOutput) Run(ctx context.Context) {
+	o.active.Store(true)
+	defer func() { o.active.Store(false) }()
+
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case snapshot := <-o.snapshots:
+			o.add(snapshot)
+		case <-ticker.C:
+			o.export(ctx)
+		case <-ctx.Done():
+			// Export the latest values once more before shutting down the exporter.
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), o.timeout)
+			defer cancel()
+			o.export(shutdownCtx)
+			if err := o.exporter.Shutdown(shutdownCtx); err != nil {
+				o.logger.Warn("Can not shutdown otlp exporter: " + err.Error())
+			}
+			return
+		}
+	}
+}
+
+func (o *otlpOutput) IsActive() bool {
+	return o.active.Load()
+}
+
+func (o *otlpOutput) Name() string {
+	return "otlp"
+}
+
+func (o *otlpOutput) add(snapshot *Snapshot) {
+	key := SnapshotKey{source: snapshot.source, target: snapshot.destination}
+	if _, ok := o.startTimes[key]; !ok {
+		o.startTimes[key] = snapshot.timestamp
+	}
+	o.latest[key] = snapshot
+}
+
+func (o *otlpOutput) export(ctx context.Context) {
+	if len(o.latest) == 0 {
+		return
+	}
+	metrics := o.toResourceMetrics()
+	if err := o.exporter.Export(ctx, metrics); err != nil {
+		o.logger.Warn("Can not export otlp metrics: " + err.Error())
+	}
+}
+
+// toResourceMetrics converts the latest snapshots into OpenTelemetry metrics. All snapshots with the same metric name
+// are combined into a single metric with one data point per group address and device. Counters are exported as
+// cumulative monotonic sums and all other values as gauges.
+func (o *otlpOutput) toResourceMetrics() *metricdata.ResourceMetrics {
+	var keys []SnapshotKey
+	for key := range o.latest {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b SnapshotKey) int {
+		if c := strings.Compare(o.latest[a].name, o.latest[b].name); c != 0 {
+			return c
+		}
+		if a.target != b.target {
+			return int(a.target) - int(b.target)
+		}
+		return int(a.source) - int(b.source)
+	})
+
+	var metrics []metricdata.Metrics
+	for len(keys) > 0 {
+		// All keys with the same metric name are next to each other.
+		first := o.latest[keys[0]]
+		count := 1
+		for count < len(keys) && o.latest[keys[count]].name == first.name {
+			count++
+		}
+		metrics = append(metrics, o.toMetric(first, keys[:count]))
+		keys = keys[count:]
+	}
+
+	return &metricdata.ResourceMetrics{
+		Resource:     o.resource,
+		ScopeMetrics: []metricdata.ScopeMetrics{{Scope: otlpScope, Metrics: metrics}},
+	}
+}
+
+// toMetric creates a single metric which contains the data points of all given keys. The unit, description and metric
+// type are taken from the first snapshot.
+func (o *otlpOutput) toMetric(first *Snapshot, keys []SnapshotKey) metricdata.Metrics {
+	metric := metricdata.Metrics{Name: first.name, Description: first.config.Comment}
+	if datapoint, ok := dpt.Produce(first.config.DPT); ok {
+		metric.Unit = datapoint.Unit()
+	}
+
+	counter := strings.ToLower(first.config.MetricType) == "counter"
+	var points []metricdata.DataPoint[float64]
+	for _, key := range keys {
+		snapshot := o.latest[key]
+		point := metricdata.DataPoint[float64]{
+			Attributes: o.attributesFor(snapshot),
+			Time:       snapshot.timestamp,
+			Value:      snapshot.value,
+		}
+		if counter {
+			point.StartTime = o.startTimes[key]
+		}
+		points = append(points, point)
+	}
+
+	if counter {
+		metric.Data = metricdata.Sum[float64]{
+			DataPoints:  points,
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+		}
+	} else {
+		metric.Data = metricdata.Gauge[float64]{DataPoints: points}
+	}
+	return metric
+}
+
+// attributesFor returns the labels and the physical address of the snapshot as data point attributes.
+func (o *otlpOutput) attributesFor(snapshot *Snapshot) attribute.Set {
+	attributes := []attribute.KeyValue{attribute.String("physicalAddress", snapshot.source.String())}
+	for name, value := range snapshot.config.Labels {
+		attributes = append(attributes, attribute.String(name, value))
+	}
+	return attribute.NewSet(attributes...)
+}
@@ -0,0 +1,189 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpReceiver collects all received export requests of the grpc and http receivers.
+type otlpReceiver struct {
+	collectormetrics.UnimplementedMetricsServiceServer
+	lock     sync.Mutex
+	requests []*collectormetrics.ExportMetricsServiceRequest
+}
+
+func (r *otlpReceiver) Export(_ context.Context, request *collectormetrics.ExportMetricsServiceRequest) (*collectormetrics.ExportMetricsServiceResponse, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.requests = append(r.requests, request)
+	return &collectormetrics.ExportMetricsServiceResponse{}, nil
+}
+
+func (r *otlpReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	request := &collectormetrics.ExportMetricsServiceRequest{}
+	if err := proto.Unmarshal(body, request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	_, _ = r.Export(req.Context(), request)
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	response, _ := proto.Marshal(&collectormetrics.ExportMetricsServiceResponse{})
+	_, _ = w.Write(response)
+}
+
+func (r *otlpReceiver) lastRequest() *collectormetrics.ExportMetricsServiceRequest {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if len(r.requests) == 0 {
+		return nil
+	}
+	return r.requests[len(r.requests)-1]
+}
+
+func TestOTLPOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol OTLPProtocol
+	}{
+		{"grpc", OTLPGRPC},
+		{"http", OTLPHTTP},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &otlpReceiver{}
+			var endpoint string
+			if tt.protocol == OTLPGRPC {
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				assert.NoError(t, err)
+				server := grpc.NewServer()
+				collectormetrics.RegisterMetricsServiceServer(server, receiver)
+				go func() { _ = server.Serve(listener) }()
+				defer server.Stop()
+				endpoint = listener.Addr().String()
+			} else {
+				server := httptest.NewServer(receiver)
+				defer server.Close()
+				endpoint = strings.TrimPrefix(server.URL, "http://")
+			}
+
+			config := &Config{
+				Installation: "home",
+				Connection:   Connection{Endpoint: "192.168.1.15:3671"},
+				OTLP: &OTLPConfig{
+					Endpoint: endpoint,
+					Protocol: tt.protocol,
+					Insecure: true,
+					Interval: Duration(50 * time.Millisecond),
+				},
+			}
+			output, err := NewOTLPOutput(config)
+			assert.NoError(t, err)
+
+			timestamp := time.Unix(1700000000, 0)
+			temperature := &GroupAddressConfig{Export: true, DPT: "9.001", MetricType: "gauge", Comment: "Temperature", Labels: map[string]string{"room": "kitchen"}}
+			energy := &GroupAddressConfig{Export: true, DPT: "13.010", MetricType: "counter"}
+			output.Publish(&Snapshot{name: "knx_temperature", source: PhysicalAddress(0x1105), destination: GroupAddress(1), value: 21.5, timestamp: timestamp, config: temperature})
+			output.Publish(&Snapshot{name: "knx_energy", source: PhysicalAddress(0x1106), destination: GroupAddress(2), value: 100, timestamp: timestamp, config: energy})
+			output.Publish(&Snapshot{name: "knx_energy", source: PhysicalAddress(0x1106), destination: GroupAddress(2), value: 150, timestamp: timestamp.Add(time.Minute), config: energy})
+			output.Publish(&Snapshot{name: "knx_hidden", destination: GroupAddress(3), config: &GroupAddressConfig{}})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan bool)
+			go func() {
+				output.Run(ctx)
+				done <- true
+			}()
+			received := assert.Eventually(t, func() bool {
+				request := receiver.lastRequest()
+				return request != nil && len(request.ResourceMetrics[0].ScopeMetrics[0].Metrics) == 2 &&
+					request.ResourceMetrics[0].ScopeMetrics[0].Metrics[0].GetSum().DataPoints[0].GetAsDouble() == 150
+			}, 5*time.Second, 10*time.Millisecond)
+			cancel()
+			<-done
+			assert.False(t, output.IsActive())
+			if !received {
+				return
+			}
+
+			resourceMetrics := receiver.lastRequest().ResourceMetrics[0]
+			assert.ElementsMatch(t, []string{
+				"service.name=knx-exporter",
+				"knx.gateway.endpoint=192.168.1.15:3671",
+				"knx.installation=home",
+			}, attributeStrings(resourceMetrics.Resource.Attributes))
+
+			metrics := resourceMetrics.ScopeMetrics[0].Metrics
+			assert.Equal(t, "knx_energy", metrics[0].Name)
+			assert.Equal(t, "Wh", metrics[0].Unit)
+			sum := metrics[0].GetSum()
+			assert.True(t, sum.IsMonotonic)
+			assert.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.AggregationTemporality)
+			assert.Equal(t, uint64(timestamp.UnixNano()), sum.DataPoints[0].StartTimeUnixNano)
+			assert.Equal(t, uint64(timestamp.Add(time.Minute).UnixNano()), sum.DataPoints[0].TimeUnixNano)
+			assert.Equal(t, []string{"physicalAddress=1.1.6"}, attributeStrings(sum.DataPoints[0].Attributes))
+
+			assert.Equal(t, "knx_temperature", metrics[1].Name)
+			assert.Equal(t, "Temperature", metrics[1].Description)
+			assert.Equal(t, "°C", metrics[1].Unit)
+			gauge := metrics[1].GetGauge()
+			assert.NotNil(t, gauge)
+			assert.Equal(t, 21.5, gauge.DataPoints[0].GetAsDouble())
+			assert.Equal(t, uint64(timestamp.UnixNano()), gauge.DataPoints[0].TimeUnixNano)
+			assert.Equal(t, []string{"physicalAddress=1.1.5", "room=kitchen"}, attributeStrings(gauge.DataPoints[0].Attributes))
+		})
+	}
+}
+
+func TestNewOTLPOutput_invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *OTLPConfig
+	}{
+		{"missing endpoint", &OTLPConfig{}},
+		{"invalid protocol", &OTLPConfig{Endpoint: "localhost:4317", Protocol: "udp"}},
+		{"invalid ca file", &OTLPConfig{Endpoint: "localhost:4317", TLS: &TLSConfig{CAFile: "does-not-exist.pem"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewOTLPOutput(&Config{OTLP: tt.config})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func attributeStrings(attributes []*commonpb.KeyValue) []string {
+	var result []string
+	for _, attribute := range attributes {
+		result = append(result, attribute.Key+"="+attribute.Value.GetStringValue())
+	}
+	return result
+}
@@ -18,6 +18,8 @@ import (
 	"context"
 	"log/slog"
 	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -29,8 +31,20 @@ import (
 type Poller interface {
 	// Run starts the polling.
 	Run(ctx context.Context, client GroupClient, initialReading bool)
+	// LastReadRequest returns the time when the last read request was sent for the group address.
+	LastReadRequest(address GroupAddress) (time.Time, bool)
+	// StartupReadFinished returns true if all read requests after startup are sent.
+	StartupReadFinished() bool
+	// LastTelegram returns the time when the latest read request was sent. It is zero if nothing was sent yet.
+	LastTelegram() time.Time
+	// LastError returns the error of the latest read request if at least maxPollerFailures read requests in a row
+	// have failed. Otherwise, it returns nil as single failures are only logged.
+	LastError() error
 }
 
+// maxPollerFailures is the number of read requests in a row which must fail until the poller is reported as down.
+const maxPollerFailures = 3
+
 type poller struct {
 	client          GroupClient
 	config          *Config
@@ -38,6 +52,15 @@ type poller struct {
 	snapshotHandler MetricSnapshotHandler
 	pollingInterval time.Duration
 	metricsToPoll   GroupAddressConfigSet
+	// metricsToSchedule contains all group addresses with a ReadSchedule.
+	metricsToSchedule GroupAddressConfigSet
+	lock              sync.RWMutex
+	lastReads         map[GroupAddress]time.Time
+	lastTelegram      time.Time
+	lastError         error
+	// failures is the number of read requests in a row which have failed.
+	failures    int
+	startupRead atomic.Bool
 }
 
 // NewPoller creates a new Poller instance using the given MetricsExporter for connection handling and metrics observing.
@@ -45,11 +68,13 @@ func NewPoller(config *Config, metricsHandler MetricSnapshotHandler, messageCoun
 	metricsToPoll := getMetricsToPoll(config)
 	interval := calcPollingInterval(metricsToPoll)
 	return &poller{
-		config:          config,
-		messageCounter:  messageCounter,
-		pollingInterval: interval,
-		snapshotHandler: metricsHandler,
-		metricsToPoll:   metricsToPoll,
+		config:            config,
+		messageCounter:    messageCounter,
+		pollingInterval:   interval,
+		snapshotHandler:   metricsHandler,
+		metricsToPoll:     metricsToPoll,
+		metricsToSchedule: getMetricsToSchedule(config),
+		lastReads:         make(map[GroupAddress]time.Time),
 	}
 }
 
@@ -57,8 +82,11 @@ func (p *poller) Run(ctx context.Context, client GroupClient, initialReading boo
 	p.client = client
 	if initialReading {
 		go p.runInitialReading(ctx)
+	} else {
+		p.startupRead.Store(true)
 	}
 	go p.runPolling(ctx)
+	go p.runSchedules(ctx)
 }
 
 func (p *poller) runInitialReading(ctx context.Context) {
@@ -81,6 +109,7 @@ loop:
 		}
 	}
 	ticker.Stop()
+	p.startupRead.Store(true)
 }
 
 func (p *poller) runPolling(ctx context.Context) {
@@ -103,6 +132,9 @@ func (p *poller) runPolling(ctx context.Context) {
 func (p *poller) pollAddresses(ctx context.Context, t time.Time) {
 	for address, config := range p.metricsToPoll {
 		logger := slog.With("address", address)
+		if !inReadWindows(config.ReadWindows, t) {
+			continue
+		}
 		s := p.snapshotHandler.FindYoungestSnapshot(config.Name)
 		if s == nil {
 			logger.Log(ctx, slog.LevelDebug-2, "Initial polling of address")
@@ -123,6 +155,57 @@ func (p *poller) pollAddresses(ctx context.Context, t time.Time) {
 	}
 }
 
+func (p *poller) runSchedules(ctx context.Context) {
+	if len(p.metricsToSchedule) == 0 {
+		return
+	}
+	next := make(map[GroupAddress]time.Time)
+	now := time.Now()
+	for address, config := range p.metricsToSchedule {
+		if nextRead := config.ReadSchedule.Next(now); !nextRead.IsZero() {
+			next[address] = nextRead
+		}
+	}
+
+	for len(next) > 0 {
+		var earliest time.Time
+		for _, t := range next {
+			if earliest.IsZero() || t.Before(earliest) {
+				earliest = t
+			}
+		}
+		timer := time.NewTimer(time.Until(earliest))
+		select {
+		case t := <-timer.C:
+			p.readScheduledAddresses(ctx, t, next)
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// readScheduledAddresses reads all group addresses which are due at t and calculates their next read time. Group
+// addresses without a next read time are removed.
+func (p *poller) readScheduledAddresses(ctx context.Context, t time.Time, next map[GroupAddress]time.Time) {
+	for address, config := range p.metricsToSchedule {
+		nextRead, ok := next[address]
+		if !ok || nextRead.After(t) {
+			continue
+		}
+		if nextRead = config.ReadSchedule.Next(t); nextRead.IsZero() {
+			delete(next, address)
+		} else {
+			next[address] = nextRead
+		}
+		if !inReadWindows(config.ReadWindows, t) {
+			continue
+		}
+		slog.Log(ctx, slog.LevelDebug-2, "Read address as scheduled", "address", address, "schedule", config.ReadSchedule)
+		p.sendReadMessage(address, config)
+	}
+}
+
 func (p *poller) sendReadMessage(address GroupAddress, config *GroupAddressConfig) {
 	event := knx.GroupEvent{
 		Command: knx.GroupRead,
@@ -137,10 +220,49 @@ func (p *poller) sendReadMessage(address GroupAddress, config *GroupAddressConfi
 		event.Destination = cemi.GroupAddr(address)
 	}
 
-	if e := p.client.Send(event); e != nil {
-		slog.Info("Can not send read request: "+e.Error(), "address", address.String())
+	err := p.client.Send(event)
+	if err != nil {
+		slog.Info("Can not send read request: "+err.Error(), "address", address.String())
 	}
 	p.messageCounter.WithLabelValues("sent", "true").Inc()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	now := time.Now()
+	p.lastReads[address] = now
+	if err != nil {
+		p.lastError = err
+		p.failures++
+		return
+	}
+	p.lastTelegram = now
+	p.failures = 0
+}
+
+func (p *poller) LastTelegram() time.Time {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.lastTelegram
+}
+
+func (p *poller) LastError() error {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if p.failures < maxPollerFailures {
+		return nil
+	}
+	return p.lastError
+}
+
+func (p *poller) LastReadRequest(address GroupAddress) (time.Time, bool) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	lastRead, ok := p.lastReads[address]
+	return lastRead, ok
+}
+
+func (p *poller) StartupReadFinished() bool {
+	return p.startupRead.Load()
 }
 
 func getMetricsToRead(config *Config) GroupAddressConfigSet {
@@ -177,11 +299,31 @@ func getMetricsToPoll(config *Config) GroupAddressConfigSet {
 			ReadAddress: addressConfig.ReadAddress,
 			ReadBody:    addressConfig.ReadBody,
 			MaxAge:      Duration(interval),
+			ReadWindows: addressConfig.ReadWindows,
 		}
 	}
 	return toPoll
 }
 
+func getMetricsToSchedule(config *Config) GroupAddressConfigSet {
+	toSchedule := make(GroupAddressConfigSet)
+	for address, addressConfig := range config.AddressConfigs {
+		if !addressConfig.Export || addressConfig.ReadSchedule == nil {
+			continue
+		}
+
+		toSchedule[address] = &GroupAddressConfig{
+			Name:         config.NameFor(addressConfig),
+			ReadType:     addressConfig.ReadType,
+			ReadAddress:  addressConfig.ReadAddress,
+			ReadBody:     addressConfig.ReadBody,
+			ReadSchedule: addressConfig.ReadSchedule,
+			ReadWindows:  addressConfig.ReadWindows,
+		}
+	}
+	return toSchedule
+}
+
 func calcPollingInterval(config GroupAddressConfigSet) time.Duration {
 	var intervals []time.Duration
 	for _, ga := range config {
@@ -16,11 +16,13 @@ package knx
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robfig/cron/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/vapourismo/knx-go/knx"
 	"github.com/vapourismo/knx-go/knx/cemi"
@@ -220,4 +222,157 @@ func TestPoller_Polling(t *testing.T) {
 	p := NewPoller(config, mockSnapshotHandler, messageCounter)
 	p.Run(ctx, groupClient, true)
 	time.Sleep(5500 * time.Millisecond)
+
+	_, ok := p.LastReadRequest(GroupAddress(1))
+	assert.True(t, ok)
+	_, ok = p.LastReadRequest(GroupAddress(4))
+	assert.False(t, ok)
+}
+
+func Test_getMetricsToSchedule(t *testing.T) {
+	hourly, err := NewCronSchedule("@hourly")
+	assert.NoError(t, err)
+	config := &Config{
+		MetricsPrefix: "knx_",
+		AddressConfigs: GroupAddressConfigSet{
+			0: {Name: "a", Export: true, ReadSchedule: hourly},
+			1: {Name: "b", Export: false, ReadSchedule: hourly},
+			2: {Name: "c", Export: true, ReadActive: true, MaxAge: Duration(time.Minute)},
+		},
+	}
+	assert.Equal(t, GroupAddressConfigSet{0: {Name: "knx_a", ReadSchedule: hourly}}, getMetricsToSchedule(config))
+}
+
+func Test_poller_readScheduledAddresses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hourly, err := NewCronSchedule("0 * * * *")
+	assert.NoError(t, err)
+	workingHours, err := NewTimeWindow("08:00-18:00")
+	assert.NoError(t, err)
+	groupClient := NewMockGroupClient(ctrl)
+	p := NewPoller(&Config{
+		Connection: Connection{PhysicalAddress: PhysicalAddress(cemi.NewIndividualAddr3(2, 0, 1))},
+		AddressConfigs: GroupAddressConfigSet{
+			1: {Name: "a", Export: true, ReadSchedule: hourly},
+			2: {Name: "b", Export: true, ReadSchedule: hourly, ReadWindows: []TimeWindow{workingHours}},
+		},
+	}, nil, prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"direction", "processed"})).(*poller)
+	p.client = groupClient
+
+	morning := time.Date(2026, 1, 9, 7, 0, 0, 0, time.Local)
+	next := map[GroupAddress]time.Time{1: morning, 2: morning}
+	groupClient.EXPECT().Send(knx.GroupEvent{
+		Command: knx.GroupRead, Source: cemi.NewIndividualAddr3(2, 0, 1), Destination: cemi.GroupAddr(1),
+	}).Times(1)
+	p.readScheduledAddresses(context.Background(), morning, next)
+	assert.Equal(t, map[GroupAddress]time.Time{1: morning.Add(time.Hour), 2: morning.Add(time.Hour)}, next)
+
+	groupClient.EXPECT().Send(knx.GroupEvent{
+		Command: knx.GroupRead, Source: cemi.NewIndividualAddr3(2, 0, 1), Destination: cemi.GroupAddr(1),
+	}).Times(1)
+	groupClient.EXPECT().Send(knx.GroupEvent{
+		Command: knx.GroupRead, Source: cemi.NewIndividualAddr3(2, 0, 1), Destination: cemi.GroupAddr(2),
+	}).Times(1)
+	p.readScheduledAddresses(context.Background(), morning.Add(time.Hour), next)
+
+	// Nothing is due before the next full hour.
+	p.readScheduledAddresses(context.Background(), morning.Add(90*time.Minute), next)
+}
+
+func Test_poller_runSchedulesWithoutNextRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The schedule is created without NewCronSchedule which rejects expressions that never match.
+	february30, err := cron.ParseStandard("0 0 30 2 *")
+	assert.NoError(t, err)
+	never := &CronSchedule{expression: "0 0 30 2 *", schedule: february30}
+	groupClient := NewMockGroupClient(ctrl)
+	p := NewPoller(&Config{
+		AddressConfigs: GroupAddressConfigSet{
+			1: {Name: "a", Export: true, ReadSchedule: never},
+		},
+	}, nil, prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"direction", "processed"})).(*poller)
+	p.client = groupClient
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		p.runSchedules(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("runSchedules must return if no group address has a next read time")
+	}
+
+	// A group address without next read time is never due.
+	next := map[GroupAddress]time.Time{}
+	p.readScheduledAddresses(context.Background(), time.Now(), next)
+	assert.Empty(t, next)
+
+	// A due group address whose schedule has no next read time is read a last time and removed.
+	groupClient.EXPECT().Send(knx.GroupEvent{Command: knx.GroupRead, Destination: cemi.GroupAddr(1)}).Times(1)
+	next[1] = time.Now()
+	p.readScheduledAddresses(context.Background(), time.Now(), next)
+	assert.Empty(t, next)
+}
+
+func Test_poller_sendReadMessageFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	groupClient := NewMockGroupClient(ctrl)
+	p := NewPoller(&Config{}, nil, prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"direction", "processed"})).(*poller)
+	p.client = groupClient
+	config := &GroupAddressConfig{Name: "a", Export: true}
+
+	groupClient.EXPECT().Send(gomock.Any()).Return(nil)
+	p.sendReadMessage(1, config)
+	lastTelegram := p.LastTelegram()
+	assert.False(t, lastTelegram.IsZero())
+	assert.NoError(t, p.LastError())
+
+	// Single failures are not reported and do not update the time of the last telegram.
+	groupClient.EXPECT().Send(gomock.Any()).Return(fmt.Errorf("connection closed")).Times(maxPollerFailures)
+	for i := 1; i < maxPollerFailures; i++ {
+		p.sendReadMessage(1, config)
+		assert.NoError(t, p.LastError())
+	}
+	p.sendReadMessage(1, config)
+	assert.EqualError(t, p.LastError(), "connection closed")
+	assert.Equal(t, lastTelegram, p.LastTelegram())
+
+	groupClient.EXPECT().Send(gomock.Any()).Return(nil)
+	p.sendReadMessage(1, config)
+	assert.NoError(t, p.LastError())
+	assert.True(t, p.LastTelegram().After(lastTelegram) || p.LastTelegram().Equal(lastTelegram))
+}
+
+func Test_poller_pollAddressesWithinReadWindows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	workingHours, err := NewTimeWindow("Mon-Fri 08:00-18:00")
+	assert.NoError(t, err)
+	groupClient := NewMockGroupClient(ctrl)
+	mockSnapshotHandler := NewMockMetricSnapshotHandler(ctrl)
+	p := NewPoller(&Config{
+		AddressConfigs: GroupAddressConfigSet{
+			1: {Name: "a", Export: true, ReadActive: true, MaxAge: Duration(time.Minute), ReadWindows: []TimeWindow{workingHours}},
+		},
+	}, mockSnapshotHandler, prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"direction", "processed"})).(*poller)
+	p.client = groupClient
+
+	mockSnapshotHandler.EXPECT().FindYoungestSnapshot("a").Return(nil).Times(1)
+	groupClient.EXPECT().Send(knx.GroupEvent{Command: knx.GroupRead, Destination: cemi.GroupAddr(1)}).Times(1)
+
+	// 2026-01-09 is a friday.
+	p.pollAddresses(context.Background(), time.Date(2026, 1, 9, 12, 0, 0, 0, time.Local))
+	p.pollAddresses(context.Background(), time.Date(2026, 1, 9, 20, 0, 0, 0, time.Local))
+	p.pollAddresses(context.Background(), time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local))
 }
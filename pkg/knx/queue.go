@@ -0,0 +1,115 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SnapshotQueue decouples the Listener from the MetricSnapshotHandler so that a slow handler
+// do not stall reading telegrams from the knx connection.
+type SnapshotQueue interface {
+	prometheus.Collector
+
+	// Push adds a new snapshot to the queue. If the queue is full it depends on the OverflowPolicy
+	// whether a snapshot gets dropped or Push waits until there is room for it or the context is done.
+	Push(ctx context.Context, snapshot *Snapshot)
+}
+
+type snapshotQueue struct {
+	metricsChan chan *Snapshot
+	policy      OverflowPolicy
+	length      prometheus.GaugeFunc
+	capacity    prometheus.GaugeFunc
+	dropped     prometheus.Counter
+}
+
+// NewSnapshotQueue creates a new SnapshotQueue which sends all snapshots into the given channel. The size of the queue
+// is defined by the capacity of the channel.
+func NewSnapshotQueue(metricsChan chan *Snapshot, policy OverflowPolicy) SnapshotQueue {
+	return &snapshotQueue{
+		metricsChan: metricsChan,
+		policy:      policy,
+		length: prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "knx",
+			Name:      "snapshot_queue_length",
+			Help:      "Number of snapshots waiting to be processed.",
+		}, func() float64 { return float64(len(metricsChan)) }),
+		capacity: prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "knx",
+			Name:      "snapshot_queue_capacity",
+			Help:      "Maximum number of snapshots that can wait to be processed.",
+		}, func() float64 { return float64(cap(metricsChan)) }),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   "knx",
+			Name:        "snapshots_dropped",
+			Help:        "Number of snapshots that were dropped because the snapshot queue was full.",
+			ConstLabels: prometheus.Labels{"policy": string(policy)},
+		}),
+	}
+}
+
+func (q *snapshotQueue) Push(ctx context.Context, snapshot *Snapshot) {
+	switch q.policy {
+	case DropNewest:
+		select {
+		case q.metricsChan <- snapshot:
+		default:
+			q.dropped.Inc()
+		}
+	case DropOldest:
+		q.pushDropOldest(snapshot)
+	default:
+		select {
+		case q.metricsChan <- snapshot:
+		case <-ctx.Done():
+		}
+	}
+}
+
+func (q *snapshotQueue) pushDropOldest(snapshot *Snapshot) {
+	for {
+		select {
+		case q.metricsChan <- snapshot:
+			return
+		default:
+		}
+
+		select {
+		case <-q.metricsChan:
+			q.dropped.Inc()
+		default:
+			if cap(q.metricsChan) == 0 {
+				// there is nothing buffered that can be dropped instead.
+				q.dropped.Inc()
+				return
+			}
+		}
+	}
+}
+
+func (q *snapshotQueue) Describe(ch chan<- *prometheus.Desc) {
+	q.length.Describe(ch)
+	q.capacity.Describe(ch)
+	q.dropped.Describe(ch)
+}
+
+func (q *snapshotQueue) Collect(ch chan<- prometheus.Metric) {
+	q.length.Collect(ch)
+	q.capacity.Collect(ch)
+	q.dropped.Collect(ch)
+}
@@ -0,0 +1,83 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_snapshotQueue_Push(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      OverflowPolicy
+		size        int
+		pushes      []string
+		wantQueued  []string
+		wantDropped float64
+	}{
+		{"drop oldest not full", DropOldest, 3, []string{"a", "b"}, []string{"a", "b"}, 0},
+		{"drop oldest full", DropOldest, 2, []string{"a", "b", "c", "d"}, []string{"c", "d"}, 2},
+		{"drop oldest unbuffered", DropOldest, 0, []string{"a"}, []string{}, 1},
+		{"drop newest not full", DropNewest, 3, []string{"a", "b"}, []string{"a", "b"}, 0},
+		{"drop newest full", DropNewest, 2, []string{"a", "b", "c", "d"}, []string{"a", "b"}, 2},
+		{"block not full", Block, 2, []string{"a", "b"}, []string{"a", "b"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metricsChan := make(chan *Snapshot, tt.size)
+			queue := NewSnapshotQueue(metricsChan, tt.policy)
+
+			for _, name := range tt.pushes {
+				queue.Push(context.Background(), &Snapshot{name: name})
+			}
+			close(metricsChan)
+
+			queued := []string{}
+			for s := range metricsChan {
+				queued = append(queued, s.name)
+			}
+			assert.Equal(t, tt.wantQueued, queued)
+			assert.Equal(t, tt.wantDropped, testutil.ToFloat64(queue.(*snapshotQueue).dropped))
+		})
+	}
+}
+
+func Test_snapshotQueue_PushBlockCancelled(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelFunc()
+
+	metricsChan := make(chan *Snapshot, 1)
+	queue := NewSnapshotQueue(metricsChan, Block)
+	queue.Push(ctx, &Snapshot{name: "a"})
+	queue.Push(ctx, &Snapshot{name: "b"})
+
+	assert.Len(t, metricsChan, 1)
+	assert.Equal(t, "a", (<-metricsChan).name)
+}
+
+func Test_snapshotQueue_Collect(t *testing.T) {
+	metricsChan := make(chan *Snapshot, 4)
+	queue := NewSnapshotQueue(metricsChan, DropNewest)
+	queue.Push(context.Background(), &Snapshot{name: "a"})
+
+	assert.Equal(t, 3, testutil.CollectAndCount(queue))
+	assert.Equal(t, float64(1), testutil.ToFloat64(queue.(*snapshotQueue).length))
+	assert.Equal(t, float64(4), testutil.ToFloat64(queue.(*snapshotQueue).capacity))
+}
@@ -0,0 +1,209 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// defaultRangeLabels are the label names for the group range levels if the ConvertRules do not define others.
+var defaultRangeLabels = []string{"floor", "room"}
+
+var validLabelRegex = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+// ConvertRules defines how the settings of converted group addresses are derived from the group address export.
+type ConvertRules struct {
+	// RangeLabels defines the label names for the names of the group ranges by their level. The first entry is used
+	// for the main groups, the second for the middle groups. Empty entries skip the level. Default is floor and room.
+	RangeLabels []string
+	// RangePrefix defines the levels of the group ranges whose names are prepended to the metric name. i.e. [1] uses
+	// the name of the middle group like "Kitchen_Temperature".
+	RangePrefix []int `json:",omitempty"`
+	// Collisions defines how to resolve group addresses with colliding metric names and labels.
+	Collisions CollisionStrategy `json:",omitempty"`
+	// Rules are applied in their order to every group address. Later rules overwrite the settings of earlier ones.
+	Rules []ConvertRule
+}
+
+// ConvertRule sets the defined settings for all group addresses which matches all its conditions.
+type ConvertRule struct {
+	// DPT matches the data point type of the group address. It can either be a specific type like "9.001" or a whole
+	// family like "9.*".
+	DPT string `json:",omitempty"`
+	// Name is a regular expression which must match the name of the group address within the ETS.
+	Name string `json:",omitempty"`
+	// Range is a regular expression which must match the names of all group ranges joined by "/".
+	Range string `json:",omitempty"`
+
+	// Export sets GroupAddressConfig.Export if defined.
+	Export *bool `json:",omitempty"`
+	// MetricType sets GroupAddressConfig.MetricType if defined.
+	MetricType string `json:",omitempty"`
+	// ReadStartup sets GroupAddressConfig.ReadStartup if defined.
+	ReadStartup *bool `json:",omitempty"`
+	// ReadActive sets GroupAddressConfig.ReadActive if defined.
+	ReadActive *bool `json:",omitempty"`
+	// MaxAge sets GroupAddressConfig.MaxAge if defined.
+	MaxAge Duration `json:",omitempty"`
+	// Labels are added to GroupAddressConfig.Labels.
+	Labels map[string]string `json:",omitempty"`
+
+	nameRegex  *regexp.Regexp
+	rangeRegex *regexp.Regexp
+}
+
+// ReadConvertRules reads the rules file and validates all rules within it.
+func ReadConvertRules(rulesFile string) (*ConvertRules, error) {
+	content, err := os.ReadFile(rulesFile)
+	if err != nil {
+		return nil, fmt.Errorf("can not read convert rules: %s", err)
+	}
+	rules := &ConvertRules{}
+	if err = yaml.Unmarshal(content, rules); err != nil {
+		return nil, fmt.Errorf("can not parse convert rules file %s: %s", rulesFile, err)
+	}
+	if err = rules.init(); err != nil {
+		return nil, fmt.Errorf("invalid convert rules file %s: %s", rulesFile, err)
+	}
+	return rules, nil
+}
+
+func (r *ConvertRules) init() error {
+	if r.RangeLabels == nil {
+		r.RangeLabels = defaultRangeLabels
+	}
+	for _, label := range r.RangeLabels {
+		if label != "" && !validLabelRegex.MatchString(label) {
+			return fmt.Errorf("range label \"%s\" is not a valid label name", label)
+		}
+	}
+
+	for _, level := range r.RangePrefix {
+		if level < 0 {
+			return fmt.Errorf("range prefix level %d must not be negative", level)
+		}
+	}
+
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		var err error
+		if rule.Name != "" {
+			if rule.nameRegex, err = regexp.Compile(rule.Name); err != nil {
+				return fmt.Errorf("invalid name regex in rule %d: %s", i, err)
+			}
+		}
+		if rule.Range != "" {
+			if rule.rangeRegex, err = regexp.Compile(rule.Range); err != nil {
+				return fmt.Errorf("invalid range regex in rule %d: %s", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// apply applies the range labels, the range prefix and all matching rules to the group address configuration.
+func (r *ConvertRules) apply(ga collectedAddress, cfg *GroupAddressConfig) {
+	if len(r.RangePrefix) > 0 {
+		name, err := normalizeMetricName(rangePrefix(r.RangePrefix, ga.Ranges) + ga.Name)
+		if err != nil {
+			slog.Info("Can not normalize group address name with range prefix: "+err.Error(), "address", ga.Address)
+		} else {
+			cfg.Name = name
+		}
+	}
+
+	labels := rangeLabels(r.RangeLabels, ga.Ranges)
+	for _, rule := range r.Rules {
+		if !rule.matches(ga, cfg.DPT) {
+			continue
+		}
+		if rule.Export != nil {
+			cfg.Export = *rule.Export
+		}
+		if rule.MetricType != "" {
+			cfg.MetricType = rule.MetricType
+		}
+		if rule.ReadStartup != nil {
+			cfg.ReadStartup = *rule.ReadStartup
+		}
+		if rule.ReadActive != nil {
+			cfg.ReadActive = *rule.ReadActive
+		}
+		if rule.MaxAge != 0 {
+			cfg.MaxAge = rule.MaxAge
+		}
+		for name, value := range rule.Labels {
+			labels[name] = value
+		}
+	}
+	if len(labels) > 0 {
+		cfg.Labels = labels
+	}
+}
+
+// rangeLabels creates the labels for the given group range names.
+func rangeLabels(labelNames []string, ranges []string) map[string]string {
+	labels := make(map[string]string)
+	for i, name := range labelNames {
+		if name == "" || i >= len(ranges) || ranges[i] == "" {
+			continue
+		}
+		labels[name] = ranges[i]
+	}
+	return labels
+}
+
+// rangePrefix joins the names of the group ranges of the given levels into a metric name prefix.
+func rangePrefix(levels []int, ranges []string) string {
+	var prefix strings.Builder
+	for _, level := range levels {
+		if level >= len(ranges) || ranges[level] == "" {
+			continue
+		}
+		prefix.WriteString(ranges[level])
+		prefix.WriteString("_")
+	}
+	return prefix.String()
+}
+
+func (r ConvertRule) matches(ga collectedAddress, dpt string) bool {
+	if r.DPT != "" && !matchDPT(r.DPT, dpt) {
+		return false
+	}
+	if r.nameRegex != nil && !r.nameRegex.MatchString(ga.Name) {
+		return false
+	}
+	if r.rangeRegex != nil && !r.rangeRegex.MatchString(strings.Join(ga.Ranges, "/")) {
+		return false
+	}
+	return true
+}
+
+// matchDPT checks if the dpt matches the pattern. The pattern can either be a specific type like "9.001" or a whole
+// family like "9.*".
+func matchDPT(pattern string, dpt string) bool {
+	patternMain, patternSub, _ := strings.Cut(pattern, ".")
+	main, sub, _ := strings.Cut(dpt, ".")
+	if patternMain != main {
+		return false
+	}
+	return patternSub == "*" || patternSub == "" || patternSub == sub
+}
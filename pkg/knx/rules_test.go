@@ -0,0 +1,220 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chr-fritz/knx-exporter/pkg/knx/export"
+)
+
+func TestConvertGroupAddresses_Rules(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "")
+	assert.NoError(t, err)
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	rules, err := ReadConvertRules("fixtures/convert-rules.yaml")
+	assert.NoError(t, err)
+	_, err = ConvertGroupAddresses("export/fixtures/ga-export-1-1.csv", tmpFile.Name(), ConvertOptions{Rules: rules})
+	assert.NoError(t, err)
+
+	expected, err := os.ReadFile("fixtures/ga-config-rules.yaml")
+	assert.NoError(t, err)
+	expected, err = yaml.YAMLToJSON(expected)
+	assert.NoError(t, err)
+	actual, err := os.ReadFile(tmpFile.Name())
+	assert.NoError(t, err)
+	actual, err = yaml.YAMLToJSON(actual)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestReadConvertRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		rangeLabels []string
+		wantErr     bool
+	}{
+		{"default range labels", "Rules: []", []string{"floor", "room"}, false},
+		{"custom range labels", "RangeLabels: [building, \"\", room]", []string{"building", "", "room"}, false},
+		{"invalid range label", "RangeLabels: [the floor]", nil, true},
+		{"invalid name regex", "Rules: [{Name: \"(\"}]", nil, true},
+		{"invalid range regex", "Rules: [{Range: \"[\"}]", nil, true},
+		{"invalid max age", "Rules: [{MaxAge: 10x}]", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "")
+			assert.NoError(t, err)
+			defer func() {
+				_ = os.Remove(tmpFile.Name())
+			}()
+			assert.NoError(t, os.WriteFile(tmpFile.Name(), []byte(tt.content), 0600))
+
+			rules, err := ReadConvertRules(tmpFile.Name())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadConvertRules() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, tt.rangeLabels, rules.RangeLabels)
+		})
+	}
+}
+
+func TestConvertRules_apply(t *testing.T) {
+	enabled := true
+	disabled := false
+	ga := collectedAddress{
+		GroupAddress: export.GroupAddress{Name: "Energy total"},
+		Ranges:       []string{"Ground floor", "Kitchen", "Meters"},
+	}
+	tests := []struct {
+		name  string
+		rules ConvertRules
+		dpt   string
+		want  GroupAddressConfig
+	}{
+		{
+			"only range labels",
+			ConvertRules{RangeLabels: []string{"floor", "room"}},
+			"13.010",
+			GroupAddressConfig{DPT: "13.010", Labels: map[string]string{"floor": "Ground floor", "room": "Kitchen"}},
+		},
+		{
+			"skip range level",
+			ConvertRules{RangeLabels: []string{"", "room", "function"}},
+			"13.010",
+			GroupAddressConfig{DPT: "13.010", Labels: map[string]string{"room": "Kitchen", "function": "Meters"}},
+		},
+		{
+			"dpt family",
+			ConvertRules{RangeLabels: []string{}, Rules: []ConvertRule{{DPT: "13.*", Export: &enabled, MetricType: "counter"}}},
+			"13.010",
+			GroupAddressConfig{DPT: "13.010", Export: true, MetricType: "counter"},
+		},
+		{
+			"specific dpt not matching",
+			ConvertRules{RangeLabels: []string{}, Rules: []ConvertRule{{DPT: "13.001", Export: &enabled}}},
+			"13.010",
+			GroupAddressConfig{DPT: "13.010"},
+		},
+		{
+			"later rules overwrite",
+			ConvertRules{RangeLabels: []string{}, Rules: []ConvertRule{
+				{DPT: "13", Export: &enabled, ReadActive: &enabled, MaxAge: Duration(time.Minute)},
+				{Name: "^Energy", Export: &disabled, Labels: map[string]string{"unit": "kWh"}},
+				{Name: "^Power", MetricType: "gauge"},
+			}},
+			"13.010",
+			GroupAddressConfig{DPT: "13.010", ReadActive: true, MaxAge: Duration(time.Minute), Labels: map[string]string{"unit": "kWh"}},
+		},
+		{
+			"range regex",
+			ConvertRules{RangeLabels: []string{}, Rules: []ConvertRule{{Range: "/Kitchen/", ReadStartup: &enabled}}},
+			"13.010",
+			GroupAddressConfig{DPT: "13.010", ReadStartup: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, tt.rules.init())
+			cfg := &GroupAddressConfig{DPT: tt.dpt}
+			tt.rules.apply(ga, cfg)
+			assert.Equal(t, tt.want, *cfg)
+		})
+	}
+}
+
+func TestConvertGroupAddresses_RangePrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ConvertOptions
+		want    map[GroupAddress]string
+		wantErr bool
+	}{
+		{
+			"main group prefix",
+			ConvertOptions{RangeLabels: []string{}, RangePrefix: []int{0}},
+			map[GroupAddress]string{1: "A_AAA", 256: "A_ABAA", 258: "C_CC", 259: "C_CC"},
+			false,
+		},
+		{
+			"suffix group address",
+			ConvertOptions{RangeLabels: []string{}, RangePrefix: []int{0}, Collisions: SuffixAddress},
+			map[GroupAddress]string{1: "A_AAA", 256: "A_ABAA", 258: "C_CC", 259: "C_CC_0_1_3"},
+			false,
+		},
+		{
+			"skip missing levels",
+			ConvertOptions{RangeLabels: []string{}, RangePrefix: []int{2}},
+			map[GroupAddress]string{1: "AAA", 256: "ABA_ABAA", 258: "CC", 259: "CC"},
+			false,
+		},
+		{
+			"duplicates with range labels",
+			ConvertOptions{RangeLabels: []string{"floor", "room"}, Collisions: SuffixAddress},
+			map[GroupAddress]string{1: "AAA", 256: "ABAA", 258: "CC", 259: "CC_0_1_3"},
+			false,
+		},
+		{
+			"range labels",
+			ConvertOptions{Collisions: AddRangeLabels},
+			map[GroupAddress]string{1: "AAA", 256: "ABAA", 258: "CC", 259: "CC_0_1_3"},
+			false,
+		},
+		{"fail on collisions", ConvertOptions{Collisions: FailOnCollision}, nil, true},
+		{"invalid range level", ConvertOptions{RangePrefix: []int{-1}}, nil, true},
+		{"invalid range label", ConvertOptions{RangeLabels: []string{"1st"}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "")
+			assert.NoError(t, err)
+			defer func() {
+				_ = os.Remove(tmpFile.Name())
+			}()
+
+			_, err = ConvertGroupAddresses("fixtures/ga-export.xml", tmpFile.Name(), tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConvertGroupAddresses() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			content, err := os.ReadFile(tmpFile.Name())
+			assert.NoError(t, err)
+			config := struct{ AddressConfigs GroupAddressConfigSet }{}
+			assert.NoError(t, yaml.Unmarshal(content, &config))
+			names := make(map[GroupAddress]string)
+			for address, cfg := range config.AddressConfigs {
+				names[address] = cfg.Name
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
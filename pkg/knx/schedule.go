@@ -0,0 +1,210 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// CronSchedule is a cron expression with five fields or a descriptor like @hourly.
+type CronSchedule struct {
+	expression string
+	schedule   cron.Schedule
+}
+
+// NewCronSchedule parses the cron expression.
+func NewCronSchedule(expression string) (*CronSchedule, error) {
+	schedule, err := cron.ParseStandard(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression \"%s\": %s", expression, err)
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("invalid cron expression \"%s\": it never matches", expression)
+	}
+	return &CronSchedule{expression: expression, schedule: schedule}, nil
+}
+
+// Next returns the next time after t which matches the schedule.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	return s.schedule.Next(t)
+}
+
+func (s *CronSchedule) String() string {
+	return s.expression
+}
+
+func (s CronSchedule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.expression)
+}
+
+func (s *CronSchedule) UnmarshalJSON(data []byte) error {
+	var expression string
+	if err := json.Unmarshal(data, &expression); err != nil {
+		return err
+	}
+	schedule, err := NewCronSchedule(expression)
+	if err != nil {
+		return err
+	}
+	*s = *schedule
+	return nil
+}
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// TimeWindow is a daily time range in the local time of the exporter which can be limited to some weekdays. i.e.
+// "Mon-Fri 07:00-18:00", "Sat,Sun 09:00-12:00" or "22:00-06:00". If the end is before the start, the window lasts
+// until the end on the next day.
+type TimeWindow struct {
+	// Weekdays on which the window starts. All days if empty.
+	Weekdays []time.Weekday
+	// Start of the window as offset since midnight.
+	Start time.Duration
+	// End of the window as offset since midnight.
+	End time.Duration
+}
+
+// NewTimeWindow parses a time window like "Mon-Fri 07:00-18:00".
+func NewTimeWindow(str string) (TimeWindow, error) {
+	window := TimeWindow{}
+	fields := strings.Fields(str)
+	if len(fields) == 0 || len(fields) > 2 {
+		return window, fmt.Errorf("invalid time window \"%s\"", str)
+	}
+	if len(fields) == 2 {
+		weekdays, err := parseWeekdays(fields[0])
+		if err != nil {
+			return window, fmt.Errorf("invalid time window \"%s\": %s", str, err)
+		}
+		window.Weekdays = weekdays
+	}
+
+	start, end, found := strings.Cut(fields[len(fields)-1], "-")
+	if !found {
+		return window, fmt.Errorf("invalid time window \"%s\": missing end time", str)
+	}
+	var err error
+	if window.Start, err = parseTimeOfDay(start); err != nil {
+		return window, fmt.Errorf("invalid time window \"%s\": %s", str, err)
+	}
+	if window.End, err = parseTimeOfDay(end); err != nil {
+		return window, fmt.Errorf("invalid time window \"%s\": %s", str, err)
+	}
+	if window.Start == window.End {
+		return window, fmt.Errorf("invalid time window \"%s\": start and end are equal", str)
+	}
+	return window, nil
+}
+
+func parseWeekdays(str string) ([]time.Weekday, error) {
+	var weekdays []time.Weekday
+	for _, part := range strings.Split(str, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		from := slices.Index(weekdayNames, strings.ToLower(first))
+		if from < 0 {
+			return nil, fmt.Errorf("invalid weekday \"%s\"", first)
+		}
+		to := from
+		if isRange {
+			if to = slices.Index(weekdayNames, strings.ToLower(last)); to < 0 {
+				return nil, fmt.Errorf("invalid weekday \"%s\"", last)
+			}
+		}
+		for day := from; ; day = (day + 1) % len(weekdayNames) {
+			if !slices.Contains(weekdays, time.Weekday(day)) {
+				weekdays = append(weekdays, time.Weekday(day))
+			}
+			if day == to {
+				break
+			}
+		}
+	}
+	return weekdays, nil
+}
+
+func parseTimeOfDay(str string) (time.Duration, error) {
+	if str == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time \"%s\"", str)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains returns true if the time is within the window.
+func (w TimeWindow) Contains(t time.Time) bool {
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.Start < w.End {
+		return w.startsOn(t.Weekday()) && offset >= w.Start && offset < w.End
+	}
+	if offset >= w.Start {
+		return w.startsOn(t.Weekday())
+	}
+	return offset < w.End && w.startsOn((t.Weekday()+6)%7)
+}
+
+func (w TimeWindow) startsOn(day time.Weekday) bool {
+	return len(w.Weekdays) == 0 || slices.Contains(w.Weekdays, day)
+}
+
+func (w TimeWindow) String() string {
+	var days []string
+	for _, day := range w.Weekdays {
+		days = append(days, day.String()[:3])
+	}
+	window := fmt.Sprintf("%02d:%02d-%02d:%02d", int(w.Start.Hours()), int(w.Start.Minutes())%60, int(w.End.Hours()), int(w.End.Minutes())%60)
+	if len(days) == 0 {
+		return window
+	}
+	return strings.Join(days, ",") + " " + window
+}
+
+func (w TimeWindow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.String())
+}
+
+func (w *TimeWindow) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	window, err := NewTimeWindow(str)
+	if err != nil {
+		return err
+	}
+	*w = window
+	return nil
+}
+
+// inReadWindows returns true if no windows are given or the time is within at least one of them.
+func inReadWindows(windows []TimeWindow, t time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	for _, window := range windows {
+		if window.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
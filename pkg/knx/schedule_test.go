@@ -0,0 +1,118 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronSchedule_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		from    time.Time
+		want    time.Time
+		wantErr bool
+	}{
+		{"every full hour", `"0 * * * *"`, time.Date(2026, 1, 5, 10, 30, 0, 0, time.Local), time.Date(2026, 1, 5, 11, 0, 0, 0, time.Local), false},
+		{"descriptor", `"@daily"`, time.Date(2026, 1, 5, 10, 30, 0, 0, time.Local), time.Date(2026, 1, 6, 0, 0, 0, 0, time.Local), false},
+		{"invalid expression", `"every hour"`, time.Time{}, time.Time{}, true},
+		{"never matches", `"0 0 30 2 *"`, time.Time{}, time.Time{}, true},
+		{"no string", `1`, time.Time{}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schedule CronSchedule
+			if err := json.Unmarshal([]byte(tt.json), &schedule); (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, tt.want, schedule.Next(tt.from))
+			marshalled, err := json.Marshal(schedule)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.json, string(marshalled))
+		})
+	}
+}
+
+func TestNewTimeWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		want    TimeWindow
+		wantStr string
+		wantErr bool
+	}{
+		{"daily", "07:00-18:30", TimeWindow{Start: 7 * time.Hour, End: 18*time.Hour + 30*time.Minute}, "07:00-18:30", false},
+		{"until midnight", "18:00-24:00", TimeWindow{Start: 18 * time.Hour, End: 24 * time.Hour}, "18:00-24:00", false},
+		{"weekday range", "Mon-Fri 07:00-18:00", TimeWindow{Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, Start: 7 * time.Hour, End: 18 * time.Hour}, "Mon,Tue,Wed,Thu,Fri 07:00-18:00", false},
+		{"weekday list", "sat,SUN 09:00-12:00", TimeWindow{Weekdays: []time.Weekday{time.Saturday, time.Sunday}, Start: 9 * time.Hour, End: 12 * time.Hour}, "Sat,Sun 09:00-12:00", false},
+		{"wrapping weekday range", "Sat-Mon 22:00-06:00", TimeWindow{Weekdays: []time.Weekday{time.Saturday, time.Sunday, time.Monday}, Start: 22 * time.Hour, End: 6 * time.Hour}, "Sat,Sun,Mon 22:00-06:00", false},
+		{"empty", "", TimeWindow{}, "", true},
+		{"missing end", "07:00", TimeWindow{}, "", true},
+		{"invalid time", "07:00-25:00", TimeWindow{}, "", true},
+		{"invalid weekday", "Mo-Fr 07:00-18:00", TimeWindow{}, "", true},
+		{"equal start and end", "07:00-07:00", TimeWindow{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTimeWindow(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewTimeWindow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+				assert.Equal(t, tt.wantStr, got.String())
+			}
+		})
+	}
+}
+
+func TestTimeWindow_Contains(t *testing.T) {
+	workingHours, _ := NewTimeWindow("Mon-Fri 07:00-18:00")
+	fridayNights, _ := NewTimeWindow("Fri 22:00-06:00")
+	// 2026-01-09 is a friday.
+	friday := func(hour, minute int) time.Time { return time.Date(2026, 1, 9, hour, minute, 0, 0, time.Local) }
+
+	tests := []struct {
+		name   string
+		window TimeWindow
+		t      time.Time
+		want   bool
+	}{
+		{"within working hours", workingHours, friday(12, 0), true},
+		{"start of working hours", workingHours, friday(7, 0), true},
+		{"end of working hours", workingHours, friday(18, 0), false},
+		{"before working hours", workingHours, friday(6, 59), false},
+		{"weekend", workingHours, friday(12, 0).AddDate(0, 0, 1), false},
+		{"friday night", fridayNights, friday(23, 0), true},
+		{"saturday morning", fridayNights, friday(5, 0).AddDate(0, 0, 1), true},
+		{"friday morning", fridayNights, friday(5, 0), false},
+		{"saturday night", fridayNights, friday(23, 0).AddDate(0, 0, 1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.window.Contains(tt.t))
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -37,12 +38,28 @@ type MetricSnapshotHandler interface {
 	// FindYoungestSnapshot finds the youngest snapshot with the given metric name.
 	// It don't matter from which device the snapshot was received.
 	FindYoungestSnapshot(name string) *Snapshot
+	// Snapshots returns all current snapshots.
+	Snapshots() []*Snapshot
 	// Run let the MetricSnapshotHandler listen for new snapshots on the Snapshot channel.
 	Run(ctx context.Context)
 	// GetMetricsChannel returns the channel to send new snapshots to this MetricSnapshotHandler.
 	GetMetricsChannel() chan *Snapshot
 	// IsActive indicates that this handler is active and waits for new metric snapshots
 	IsActive() bool
+	// AddOutput adds an output to which all new snapshots are handed over after they were added.
+	AddOutput(output SnapshotOutput)
+}
+
+// SnapshotOutput publishes all received snapshots to another system beside Prometheus.
+type SnapshotOutput interface {
+	// Publish hands over a new snapshot. It must not block the handling of further snapshots.
+	Publish(snapshot *Snapshot)
+	// Run publishes all handed over snapshots until the context is done.
+	Run(ctx context.Context)
+	// IsActive indicates that this output is running.
+	IsActive() bool
+	// Name returns the type of the output which is used as component name within the health.
+	Name() string
 }
 
 // SnapshotKey identifies all the snapshots that were received from a specific device and exported with the specific name.
@@ -65,18 +82,26 @@ type metricSnapshots struct {
 	lock         sync.RWMutex
 	snapshots    map[SnapshotKey]*Snapshot
 	descriptions map[SnapshotKey]*prometheus.Desc
-	metricsChan  chan *Snapshot
-	active       bool
+	// helps contains the help text for every metric name. Prometheus requires the same help text for all metrics with
+	// the same name even if they are from different group addresses.
+	helps       map[string]string
+	metricsChan chan *Snapshot
+	outputs     []SnapshotOutput
+	active      atomic.Bool
 }
 
-func NewMetricsSnapshotHandler() MetricSnapshotHandler {
-	return &metricSnapshots{
+// NewMetricsSnapshotHandler creates a new MetricSnapshotHandler whose metrics channel can buffer up to
+// bufferSize snapshots.
+func NewMetricsSnapshotHandler(bufferSize uint) MetricSnapshotHandler {
+	m := &metricSnapshots{
 		lock:         sync.RWMutex{},
 		snapshots:    make(map[SnapshotKey]*Snapshot),
 		descriptions: make(map[SnapshotKey]*prometheus.Desc),
-		metricsChan:  make(chan *Snapshot),
-		active:       true,
+		helps:        make(map[string]string),
+		metricsChan:  make(chan *Snapshot, bufferSize),
 	}
+	m.active.Store(true)
+	return m
 }
 
 func (m *metricSnapshots) AddSnapshot(s *Snapshot) {
@@ -86,7 +111,12 @@ func (m *metricSnapshots) AddSnapshot(s *Snapshot) {
 	_, ok := m.descriptions[key]
 
 	if !ok {
-		m.descriptions[key] = createMetric(s)
+		help, found := m.helps[s.name]
+		if !found {
+			help = s.config.Comment
+			m.helps[s.name] = help
+		}
+		m.descriptions[key] = createMetric(s, help)
 	}
 	m.snapshots[key] = s
 }
@@ -121,9 +151,20 @@ func (m *metricSnapshots) FindYoungestSnapshot(name string) *Snapshot {
 	return youngest
 }
 
+func (m *metricSnapshots) Snapshots() []*Snapshot {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	snapshots := make([]*Snapshot, 0, len(m.snapshots))
+	for _, s := range m.snapshots {
+		snapshots = append(snapshots, s)
+	}
+	return snapshots
+}
+
 func (m *metricSnapshots) Run(ctx context.Context) {
-	m.active = true
-	defer func() { m.active = false }()
+	m.active.Store(true)
+	defer func() { m.active.Store(false) }()
 loop:
 	for {
 		select {
@@ -132,6 +173,9 @@ loop:
 				return
 			}
 			m.AddSnapshot(snap)
+			for _, output := range m.outputs {
+				output.Publish(snap)
+			}
 		case <-ctx.Done():
 			break loop
 		}
@@ -139,7 +183,11 @@ loop:
 }
 
 func (m *metricSnapshots) IsActive() bool {
-	return m.active
+	return m.active.Load()
+}
+
+func (m *metricSnapshots) AddOutput(output SnapshotOutput) {
+	m.outputs = append(m.outputs, output)
 }
 
 func (m *metricSnapshots) GetMetricsChannel() chan *Snapshot {
@@ -147,12 +195,41 @@ func (m *metricSnapshots) GetMetricsChannel() chan *Snapshot {
 }
 
 func (m *metricSnapshots) Describe(ch chan<- *prometheus.Desc) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
 	for _, d := range m.descriptions {
 		ch <- d
 	}
 }
 
+// LastUpdateMetric is the name of the metric with the time of the last received value of every group address. Like all
+// other metrics of the exporter itself, i.e. knx_messages, it does not use the MetricsPrefix. So the generated rules
+// and dashboards work the same way for all configurations.
+const LastUpdateMetric = "knx_last_update_timestamp_seconds"
+
+// lastUpdateDesc describes the LastUpdateMetric. It is not part of Describe as the collector must stay unchecked for
+// the dynamically created metrics.
+var lastUpdateDesc = prometheus.NewDesc(
+	LastUpdateMetric,
+	"Unix timestamp of the last received value of the group address.",
+	[]string{"groupAddress"},
+	nil,
+)
+
 func (m *metricSnapshots) Collect(metrics chan<- prometheus.Metric) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	lastUpdates := make(map[GroupAddress]time.Time)
+	for _, s := range m.snapshots {
+		if s.timestamp.After(lastUpdates[s.destination]) {
+			lastUpdates[s.destination] = s.timestamp
+		}
+	}
+	for address, timestamp := range lastUpdates {
+		metrics <- prometheus.MustNewConstMetric(lastUpdateDesc, prometheus.GaugeValue, float64(timestamp.UnixMilli())/1000, address.String())
+	}
+
 	for k, s := range m.snapshots {
 		if s.config.WithTimestamp {
 			metrics <- prometheus.NewMetricWithTimestamp(s.timestamp, prometheus.MustNewConstMetric(m.descriptions[k], s.getValuetype(), s.value))
@@ -178,8 +255,8 @@ func (s *Snapshot) getValuetype() prometheus.ValueType {
 	return prometheus.UntypedValue
 }
 
-func createMetric(s *Snapshot) *prometheus.Desc {
-	return prometheus.NewDesc(s.name, s.config.Comment, []string{}, getSnapshotLabels(s))
+func createMetric(s *Snapshot, help string) *prometheus.Desc {
+	return prometheus.NewDesc(s.name, help, []string{}, getSnapshotLabels(s))
 }
 
 // getSnapshotLabels returns a full list of all labels that should be added to the given metric.
@@ -49,6 +49,18 @@ func (m *MockMetricSnapshotHandler) EXPECT() *MockMetricSnapshotHandlerMockRecor
 	return m.recorder
 }
 
+// AddOutput mocks base method.
+func (m *MockMetricSnapshotHandler) AddOutput(output SnapshotOutput) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddOutput", output)
+}
+
+// AddOutput indicates an expected call of AddOutput.
+func (mr *MockMetricSnapshotHandlerMockRecorder) AddOutput(output interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOutput", reflect.TypeOf((*MockMetricSnapshotHandler)(nil).AddOutput), output)
+}
+
 // AddSnapshot mocks base method.
 func (m *MockMetricSnapshotHandler) AddSnapshot(snapshot *Snapshot) {
 	m.ctrl.T.Helper()
@@ -153,3 +165,92 @@ func (mr *MockMetricSnapshotHandlerMockRecorder) Run(ctx interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockMetricSnapshotHandler)(nil).Run), ctx)
 }
+
+// Snapshots mocks base method.
+func (m *MockMetricSnapshotHandler) Snapshots() []*Snapshot {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshots")
+	ret0, _ := ret[0].([]*Snapshot)
+	return ret0
+}
+
+// Snapshots indicates an expected call of Snapshots.
+func (mr *MockMetricSnapshotHandlerMockRecorder) Snapshots() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshots", reflect.TypeOf((*MockMetricSnapshotHandler)(nil).Snapshots))
+}
+
+// MockSnapshotOutput is a mock of SnapshotOutput interface.
+type MockSnapshotOutput struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotOutputMockRecorder
+}
+
+// MockSnapshotOutputMockRecorder is the mock recorder for MockSnapshotOutput.
+type MockSnapshotOutputMockRecorder struct {
+	mock *MockSnapshotOutput
+}
+
+// NewMockSnapshotOutput creates a new mock instance.
+func NewMockSnapshotOutput(ctrl *gomock.Controller) *MockSnapshotOutput {
+	mock := &MockSnapshotOutput{ctrl: ctrl}
+	mock.recorder = &MockSnapshotOutputMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSnapshotOutput) EXPECT() *MockSnapshotOutputMockRecorder {
+	return m.recorder
+}
+
+// IsActive mocks base method.
+func (m *MockSnapshotOutput) IsActive() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsActive")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsActive indicates an expected call of IsActive.
+func (mr *MockSnapshotOutputMockRecorder) IsActive() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsActive", reflect.TypeOf((*MockSnapshotOutput)(nil).IsActive))
+}
+
+// Name mocks base method.
+func (m *MockSnapshotOutput) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockSnapshotOutputMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockSnapshotOutput)(nil).Name))
+}
+
+// Publish mocks base method.
+func (m *MockSnapshotOutput) Publish(snapshot *Snapshot) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", snapshot)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockSnapshotOutputMockRecorder) Publish(snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockSnapshotOutput)(nil).Publish), snapshot)
+}
+
+// Run mocks base method.
+func (m *MockSnapshotOutput) Run(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockSnapshotOutputMockRecorder) Run(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockSnapshotOutput)(nil).Run), ctx)
+}
@@ -15,6 +15,7 @@
 package knx
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -57,7 +58,7 @@ func Test_metricSnapshots_AddSnapshot(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			handler := NewMetricsSnapshotHandler()
+			handler := NewMetricsSnapshotHandler(0)
 			snapshots := handler.(*metricSnapshots)
 			snapshots.descriptions[SnapshotKey{source: 1, target: 3}] = prometheus.NewDesc("", "", []string{}, map[string]string{})
 			handler.AddSnapshot(tt.s)
@@ -67,6 +68,20 @@ func Test_metricSnapshots_AddSnapshot(t *testing.T) {
 	}
 }
 
+func Test_metricSnapshots_SameNameDifferentHelp(t *testing.T) {
+	handler := NewMetricsSnapshotHandler(0)
+	handler.AddSnapshot(&Snapshot{name: "temperature", source: 1, destination: 1, config: &GroupAddressConfig{MetricType: "gauge", Comment: "Kitchen", Labels: map[string]string{"room": "kitchen"}}})
+	handler.AddSnapshot(&Snapshot{name: "temperature", source: 1, destination: 2, config: &GroupAddressConfig{MetricType: "gauge", Comment: "Bath", Labels: map[string]string{"room": "bath"}}})
+
+	registry := prometheus.NewPedanticRegistry()
+	assert.NoError(t, registry.Register(handler))
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	assert.Len(t, families, 1)
+	assert.Equal(t, "Kitchen", families[0].GetHelp())
+	assert.Len(t, families[0].GetMetric(), 2)
+}
+
 func Test_metricSnapshots_FindSnapshot(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -211,7 +226,7 @@ func Test_metricSnapshots_Describe(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handler := NewMetricsSnapshotHandler()
+			handler := NewMetricsSnapshotHandler(0)
 			for _, snapshot := range tt.snapshots {
 				handler.AddSnapshot(snapshot)
 			}
@@ -245,6 +260,7 @@ func Test_metricSnapshots_Collect(t *testing.T) {
 				{name: "dummy", value: 1, source: 1, timestamp: testTime, config: &GroupAddressConfig{MetricType: "counter"}},
 			},
 			[]prometheus.Metric{
+				prometheus.MustNewConstMetric(lastUpdateDesc, prometheus.GaugeValue, float64(testTime.UnixMilli())/1000, "0/0/0"),
 				prometheus.MustNewConstMetric(prometheus.NewDesc("dummy", "", []string{}, map[string]string{"physicalAddress": "0.0.1"}), prometheus.CounterValue, 1),
 			},
 		},
@@ -253,13 +269,14 @@ func Test_metricSnapshots_Collect(t *testing.T) {
 				{name: "dummy", value: 1, source: 1, timestamp: testTime, config: &GroupAddressConfig{MetricType: "gauge", WithTimestamp: true}},
 			},
 			[]prometheus.Metric{
+				prometheus.MustNewConstMetric(lastUpdateDesc, prometheus.GaugeValue, float64(testTime.UnixMilli())/1000, "0/0/0"),
 				prometheus.NewMetricWithTimestamp(testTime, prometheus.MustNewConstMetric(prometheus.NewDesc("dummy", "", []string{}, map[string]string{"physicalAddress": "0.0.1"}), prometheus.GaugeValue, 1)),
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handler := NewMetricsSnapshotHandler()
+			handler := NewMetricsSnapshotHandler(0)
 			for _, snapshot := range tt.snapshots {
 				handler.AddSnapshot(snapshot)
 			}
@@ -279,3 +296,57 @@ func Test_metricSnapshots_Collect(t *testing.T) {
 		})
 	}
 }
+
+func Test_metricSnapshots_CollectWhileAdding(t *testing.T) {
+	handler := NewMetricsSnapshotHandler(0)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			handler.AddSnapshot(&Snapshot{name: "dummy", value: float64(i), source: PhysicalAddress(i), destination: GroupAddress(i), timestamp: time.Now(), config: &GroupAddressConfig{MetricType: "gauge"}})
+		}
+	}()
+	collect := func() int {
+		ch := make(chan prometheus.Metric)
+		go func() {
+			handler.Collect(ch)
+			close(ch)
+		}()
+		count := 0
+		for range ch {
+			count++
+		}
+		return count
+	}
+	for i := 0; i < 10; i++ {
+		collect()
+	}
+	<-done
+	assert.Equal(t, 200, collect())
+}
+
+func Test_metricSnapshots_RunOutputs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	snapshot := &Snapshot{name: "a", source: 1, destination: 1, config: &GroupAddressConfig{MetricType: "gauge"}}
+	published := make(chan *Snapshot, 1)
+	output := NewMockSnapshotOutput(ctrl)
+	output.EXPECT().Publish(snapshot).Do(func(s *Snapshot) { published <- s })
+
+	handler := NewMetricsSnapshotHandler(1)
+	handler.AddOutput(output)
+	go handler.Run(ctx)
+	handler.GetMetricsChannel() <- snapshot
+
+	select {
+	case s := <-published:
+		assert.Equal(t, snapshot, s)
+	case <-time.After(time.Second):
+		t.Fatal("snapshot was not handed over to the output")
+	}
+	_, err := handler.FindSnapshot(snapshot.getKey())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,133 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	_ "embed"
+	"html/template"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// defaultGroupByLabel is the label which is used to group the addresses on the status page if it exists.
+const defaultGroupByLabel = "room"
+
+//go:embed statuspage.html
+var statusPageTemplate string
+
+var statusPage = template.Must(template.New("status").Parse(statusPageTemplate))
+
+// statusPageData contains everything which is rendered on the status page.
+type statusPageData struct {
+	GroupBy       string
+	LabelNames    []string
+	Installations []statusPageInstallation
+	Now           time.Time
+}
+
+type statusPageInstallation struct {
+	Name       string
+	Connection ConnectionStatus
+	Groups     []statusPageGroup
+	Warnings   []Warning
+}
+
+type statusPageGroup struct {
+	Name      string
+	Addresses []statusPageAddress
+}
+
+type statusPageAddress struct {
+	GroupAddressStatus
+	// FormattedValue is the value including its unit.
+	FormattedValue string
+	// Age is the time since the last update rounded to seconds.
+	Age string
+}
+
+// NewStatusPageHandler creates a html page which shows the connection states, the latest values grouped by a label and
+// the recent warnings of all given exporters. The page refreshes itself every few seconds. The label can be chosen
+// using the groupBy query parameter.
+func NewStatusPageHandler(exporters []MetricsExporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var statuses []ExporterStatus
+		for _, exporter := range exporters {
+			statuses = append(statuses, exporter.Status())
+		}
+		data := newStatusPageData(statuses, r.URL.Query().Get("groupBy"), time.Now())
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := statusPage.Execute(w, data); err != nil {
+			slog.Warn("Can not render status page: " + err.Error())
+		}
+	})
+}
+
+func newStatusPageData(statuses []ExporterStatus, groupBy string, now time.Time) statusPageData {
+	var labelNames []string
+	for _, status := range statuses {
+		for _, address := range status.GroupAddresses {
+			for name := range address.Labels {
+				if !slices.Contains(labelNames, name) {
+					labelNames = append(labelNames, name)
+				}
+			}
+		}
+	}
+	slices.Sort(labelNames)
+	if groupBy == "" && len(labelNames) > 0 {
+		groupBy = labelNames[0]
+		if slices.Contains(labelNames, defaultGroupByLabel) {
+			groupBy = defaultGroupByLabel
+		}
+	}
+
+	data := statusPageData{GroupBy: groupBy, LabelNames: labelNames, Now: now}
+	for _, status := range statuses {
+		installation := statusPageInstallation{
+			Name:       status.Installation,
+			Connection: status.Connection,
+			Warnings:   status.Warnings,
+		}
+		groups := make(map[string][]statusPageAddress)
+		for _, address := range status.GroupAddresses {
+			group := address.Labels[groupBy]
+			groups[group] = append(groups[group], newStatusPageAddress(address, now))
+		}
+		for _, name := range slices.Sorted(maps.Keys(groups)) {
+			installation.Groups = append(installation.Groups, statusPageGroup{Name: name, Addresses: groups[name]})
+		}
+		data.Installations = append(data.Installations, installation)
+	}
+	return data
+}
+
+func newStatusPageAddress(status GroupAddressStatus, now time.Time) statusPageAddress {
+	address := statusPageAddress{GroupAddressStatus: status, FormattedValue: "-", Age: "never"}
+	if status.Value != nil {
+		address.FormattedValue = strconv.FormatFloat(*status.Value, 'f', -1, 64)
+		if status.Unit != "" {
+			address.FormattedValue += " " + status.Unit
+		}
+	}
+	if status.LastUpdate != nil {
+		address.Age = now.Sub(*status.LastUpdate).Round(time.Second).String()
+	}
+	return address
+}
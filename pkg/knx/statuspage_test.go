@@ -0,0 +1,114 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newStatusPageData(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	statuses := []ExporterStatus{{
+		Installation: "home",
+		GroupAddresses: []GroupAddressStatus{
+			{Address: 1, Name: "knx_temperature", Labels: map[string]string{"floor": "ground", "room": "kitchen"}, Value: ptr(21.5), Unit: "°C", LastUpdate: ptr(now.Add(-90 * time.Second))},
+			{Address: 2, Name: "knx_humidity", Labels: map[string]string{"floor": "ground", "room": "bath"}},
+			{Address: 3, Name: "knx_wind", Value: ptr(3.0)},
+		},
+	}}
+
+	tests := []struct {
+		name    string
+		groupBy string
+		want    map[string][]GroupAddress
+		wantBy  string
+	}{
+		{"default label", "", map[string][]GroupAddress{"": {3}, "bath": {2}, "kitchen": {1}}, "room"},
+		{"selected label", "floor", map[string][]GroupAddress{"": {3}, "ground": {1, 2}}, "floor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := newStatusPageData(statuses, tt.groupBy, now)
+			assert.Equal(t, tt.wantBy, data.GroupBy)
+			assert.Equal(t, []string{"floor", "room"}, data.LabelNames)
+
+			got := make(map[string][]GroupAddress)
+			for _, group := range data.Installations[0].Groups {
+				for _, address := range group.Addresses {
+					got[group.Name] = append(got[group.Name], address.Address)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_newStatusPageAddress(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name      string
+		status    GroupAddressStatus
+		wantValue string
+		wantAge   string
+	}{
+		{"no value", GroupAddressStatus{}, "-", "never"},
+		{"with unit", GroupAddressStatus{Value: ptr(21.5), Unit: "°C", LastUpdate: ptr(now.Add(-90 * time.Second))}, "21.5 °C", "1m30s"},
+		{"without unit", GroupAddressStatus{Value: ptr(1.0), LastUpdate: ptr(now)}, "1", "0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newStatusPageAddress(tt.status, now)
+			assert.Equal(t, tt.wantValue, got.FormattedValue)
+			assert.Equal(t, tt.wantAge, got.Age)
+		})
+	}
+}
+
+func TestNewStatusPageHandler(t *testing.T) {
+	kitchen := &GroupAddressConfig{Name: "temperature", DPT: "9.001", Export: true, Labels: map[string]string{"room": "<kitchen>"}}
+	l := NewListener(&Config{}, nil, nil, nil).(*listener)
+	l.warnings = []Warning{{Time: time.Now(), Source: 0x1105, Destination: 2, Message: "can not unpack data"}}
+	exporter := &metricsExporter{
+		config: &Config{
+			Connection:     Connection{Type: Tunnel, Endpoint: "192.168.1.15:3671"},
+			AddressConfigs: GroupAddressConfigSet{1: kitchen},
+		},
+		metrics:  NewMetricsSnapshotHandler(1),
+		listener: l,
+	}
+	exporter.metrics.AddSnapshot(&Snapshot{name: "temperature", source: 0x1105, destination: 1, value: 21.5, timestamp: time.Now(), config: kitchen})
+
+	server := httptest.NewServer(NewStatusPageHandler([]MetricsExporter{exporter}))
+	defer server.Close()
+	response, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "text/html; charset=utf-8", response.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), "21.5 °C")
+	assert.Contains(t, string(body), "&lt;kitchen&gt;")
+	assert.Contains(t, string(body), "disconnected (not connected)")
+	assert.Contains(t, string(body), "can not unpack data")
+}
@@ -0,0 +1,198 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/vapourismo/knx-go/knx"
+)
+
+// telegramStreamBufferSize is the number of telegrams which are buffered for every stream client.
+const telegramStreamBufferSize = 64
+
+// telegramStreamKeepAlive is the interval of the comments which keep idle streams open.
+const telegramStreamKeepAlive = 30 * time.Second
+
+// TelegramEvent describes a single received telegram. Telegrams of configured group addresses additionally contain the
+// decoded value.
+type TelegramEvent struct {
+	Installation string          `json:"installation,omitempty"`
+	Time         time.Time       `json:"time"`
+	Command      string          `json:"command"`
+	Source       PhysicalAddress `json:"source"`
+	Destination  GroupAddress    `json:"destination"`
+	// Data is the raw payload of the telegram as hex string.
+	Data  string   `json:"data"`
+	Name  string   `json:"name,omitempty"`
+	DPT   string   `json:"dpt,omitempty"`
+	Value *float64 `json:"value,omitempty"`
+	// Text is the decoded value as human-readable text including its unit.
+	Text string `json:"text,omitempty"`
+	Unit string `json:"unit,omitempty"`
+}
+
+// TelegramFilter selects the telegrams of a stream. Empty fields match all telegrams.
+type TelegramFilter struct {
+	// From is the first group address of the range of destinations.
+	From GroupAddress
+	// To is the last group address of the range of destinations.
+	To GroupAddress
+	// Sources are the physical addresses of the senders.
+	Sources []PhysicalAddress
+}
+
+func (f TelegramFilter) matches(event *TelegramEvent) bool {
+	if event.Destination < f.From || (f.To != 0 && event.Destination > f.To) {
+		return false
+	}
+	return len(f.Sources) == 0 || slices.Contains(f.Sources, event.Source)
+}
+
+// telegramBroadcaster hands over the received telegrams to all subscribed streams.
+type telegramBroadcaster struct {
+	lock        sync.RWMutex
+	subscribers map[chan<- TelegramEvent]TelegramFilter
+}
+
+func newTelegramBroadcaster() *telegramBroadcaster {
+	return &telegramBroadcaster{subscribers: make(map[chan<- TelegramEvent]TelegramFilter)}
+}
+
+// Subscribe sends all matching telegrams to the channel until the returned function is called.
+func (b *telegramBroadcaster) Subscribe(telegrams chan<- TelegramEvent, filter TelegramFilter) func() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.subscribers[telegrams] = filter
+	return func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		delete(b.subscribers, telegrams)
+	}
+}
+
+// Publish sends the telegram to all subscribers with a matching filter. It drops the telegram for subscribers which
+// are not fast enough instead of blocking the listener.
+func (b *telegramBroadcaster) Publish(event *TelegramEvent) {
+	if b == nil {
+		return
+	}
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	for telegrams, filter := range b.subscribers {
+		if !filter.matches(event) {
+			continue
+		}
+		select {
+		case telegrams <- *event:
+		default:
+			slog.Debug("Drop telegram as the stream is too slow", "destination", event.Destination)
+		}
+	}
+}
+
+func newTelegramEvent(installation string, event knx.GroupEvent) *TelegramEvent {
+	return &TelegramEvent{
+		Installation: installation,
+		Time:         time.Now(),
+		Command:      event.Command.String(),
+		Source:       PhysicalAddress(event.Source),
+		Destination:  GroupAddress(event.Destination),
+		Data:         hex.EncodeToString(event.Data),
+	}
+}
+
+// parseTelegramFilter reads the filter from the query parameters from, to and source. The source parameter can be
+// repeated to select multiple senders.
+func parseTelegramFilter(query url.Values) (TelegramFilter, error) {
+	filter := TelegramFilter{}
+	var err error
+	if from := query.Get("from"); from != "" {
+		if filter.From, err = NewGroupAddress(from); err != nil {
+			return filter, fmt.Errorf("invalid group address \"%s\": %s", from, err)
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if filter.To, err = NewGroupAddress(to); err != nil {
+			return filter, fmt.Errorf("invalid group address \"%s\": %s", to, err)
+		}
+	}
+	for _, source := range query["source"] {
+		address, err := NewPhysicalAddress(source)
+		if err != nil {
+			return filter, fmt.Errorf("invalid physical address \"%s\": %s", source, err)
+		}
+		filter.Sources = append(filter.Sources, address)
+	}
+	return filter, nil
+}
+
+// streamTelegrams sends all received telegrams of the exporters as server-sent events until the client disconnects.
+func streamTelegrams(exporters []MetricsExporter, w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTelegramFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	telegrams := make(chan TelegramEvent, telegramStreamBufferSize)
+	for _, exporter := range exporters {
+		unsubscribe := exporter.Subscribe(telegrams, filter)
+		defer unsubscribe()
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	controller := http.NewResponseController(w)
+	if err = controller.Flush(); err != nil {
+		slog.Warn("Can not stream telegrams: " + err.Error())
+		return
+	}
+
+	keepAlive := time.NewTicker(telegramStreamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case telegram := <-telegrams:
+			data, err := json.Marshal(telegram)
+			if err != nil {
+				slog.Warn("Can not marshal telegram: " + err.Error())
+				continue
+			}
+			_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+			if err == nil {
+				err = controller.Flush()
+			}
+			if err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err = fmt.Fprint(w, ": keep-alive\n\n"); err != nil || controller.Flush() != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
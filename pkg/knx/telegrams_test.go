@@ -0,0 +1,172 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/vapourismo/knx-go/knx"
+	"github.com/vapourismo/knx-go/knx/cemi"
+)
+
+func TestTelegramFilter_matches(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter TelegramFilter
+		event  TelegramEvent
+		want   bool
+	}{
+		{"empty filter", TelegramFilter{}, TelegramEvent{Source: 0x1101, Destination: 0x0901}, true},
+		{"within range", TelegramFilter{From: 0x0900, To: 0x09ff}, TelegramEvent{Destination: 0x0901}, true},
+		{"before range", TelegramFilter{From: 0x0900, To: 0x09ff}, TelegramEvent{Destination: 0x0801}, false},
+		{"after range", TelegramFilter{From: 0x0900, To: 0x09ff}, TelegramEvent{Destination: 0x0a01}, false},
+		{"open range", TelegramFilter{From: 0x0900}, TelegramEvent{Destination: 0x0a01}, true},
+		{"matching source", TelegramFilter{Sources: []PhysicalAddress{0x1101, 0x1102}}, TelegramEvent{Source: 0x1102}, true},
+		{"other source", TelegramFilter{Sources: []PhysicalAddress{0x1101}}, TelegramEvent{Source: 0x1102}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.matches(&tt.event))
+		})
+	}
+}
+
+func Test_parseTelegramFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    TelegramFilter
+		wantErr bool
+	}{
+		{"empty", "", TelegramFilter{}, false},
+		{"range", "from=1/1/0&to=1/1/255", TelegramFilter{From: 0x0900, To: 0x09ff}, false},
+		{"sources", "source=1.1.1&source=1.1.2", TelegramFilter{Sources: []PhysicalAddress{0x1101, 0x1102}}, false},
+		{"invalid from", "from=invalid", TelegramFilter{}, true},
+		{"invalid to", "to=1/2/3/4", TelegramFilter{}, true},
+		{"invalid source", "source=1/1/1", TelegramFilter{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			got, err := parseTelegramFilter(query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseTelegramFilter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_telegramBroadcaster(t *testing.T) {
+	b := newTelegramBroadcaster()
+	all := make(chan TelegramEvent, 1)
+	filtered := make(chan TelegramEvent, 1)
+	unsubscribeAll := b.Subscribe(all, TelegramFilter{})
+	unsubscribeFiltered := b.Subscribe(filtered, TelegramFilter{From: 2, To: 2})
+	defer unsubscribeFiltered()
+
+	b.Publish(&TelegramEvent{Destination: 1})
+	assert.Equal(t, GroupAddress(1), (<-all).Destination)
+	assert.Empty(t, filtered)
+
+	// The second telegram is dropped as nobody reads the channel.
+	b.Publish(&TelegramEvent{Destination: 2})
+	b.Publish(&TelegramEvent{Destination: 2, Data: "dropped"})
+	assert.Equal(t, "", (<-filtered).Data)
+	assert.Equal(t, "", (<-all).Data)
+
+	unsubscribeAll()
+	b.Publish(&TelegramEvent{Destination: 3})
+	assert.Empty(t, all)
+}
+
+func Test_listener_publishesTelegrams(t *testing.T) {
+	telegrams := newTelegramBroadcaster()
+	received := make(chan TelegramEvent, 3)
+	defer telegrams.Subscribe(received, TelegramFilter{})()
+
+	l := NewListener(
+		&Config{MetricsPrefix: "knx_", AddressConfigs: GroupAddressConfigSet{1: {Name: "temperature", DPT: "9.001", Export: true}}},
+		NewSnapshotQueue(make(chan *Snapshot, 1), DropNewest),
+		prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"direction", "processed"}),
+		telegrams,
+	).(*listener)
+	l.handleEvent(context.Background(), knx.GroupEvent{Command: knx.GroupWrite, Source: 0x1101, Destination: 1, Data: []byte{0, 0x0c, 0x33}})
+	l.handleEvent(context.Background(), knx.GroupEvent{Command: knx.GroupRead, Source: 0x1101, Destination: 1, Data: []byte{0}})
+	l.handleEvent(context.Background(), knx.GroupEvent{Command: knx.GroupWrite, Source: 0x1102, Destination: cemi.GroupAddr(255), Data: []byte{0x01, 0xff}})
+
+	written := <-received
+	assert.Equal(t, "Write", written.Command)
+	assert.Equal(t, "knx_temperature", written.Name)
+	assert.Equal(t, "000c33", written.Data)
+	assert.Equal(t, ptr(21.5), written.Value)
+	assert.Equal(t, "°C", written.Unit)
+
+	read := <-received
+	assert.Equal(t, "Read", read.Command)
+	assert.Equal(t, "knx_temperature", read.Name)
+	assert.Nil(t, read.Value)
+
+	unknown := <-received
+	assert.Equal(t, GroupAddress(255), unknown.Destination)
+	assert.Equal(t, "01ff", unknown.Data)
+	assert.Empty(t, unknown.Name)
+}
+
+func TestNewAPIHandler_telegrams(t *testing.T) {
+	exporter := &metricsExporter{config: &Config{}, telegrams: newTelegramBroadcaster()}
+	server := httptest.NewServer(NewAPIHandler([]MetricsExporter{exporter}))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/api/v1/telegrams?from=invalid")
+	assert.NoError(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/telegrams?source=1.1.2", nil)
+	assert.NoError(t, err)
+	response, err = http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	exporter.telegrams.Publish(&TelegramEvent{Source: 0x1101, Destination: 1, Data: "00"})
+	exporter.telegrams.Publish(&TelegramEvent{Source: 0x1102, Destination: 2, Data: "01"})
+
+	line, err := bufio.NewReader(response.Body).ReadString('\n')
+	assert.NoError(t, err)
+	var got TelegramEvent
+	assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &got))
+	assert.Equal(t, PhysicalAddress(0x1102), got.Source)
+	assert.Equal(t, "01", got.Data)
+}
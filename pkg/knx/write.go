@@ -0,0 +1,163 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/vapourismo/knx-go/knx/dpt"
+)
+
+// WriteRequest is the body of a request to the write api.
+type WriteRequest struct {
+	// Installation selects the KNX installation. It can be empty if there is only a single installation.
+	Installation string       `json:"installation,omitempty"`
+	Address      GroupAddress `json:"address"`
+	// Value is the human-readable value. It can be a json string, number or boolean. i.e. "21.5", 21.5, true or "on"
+	Value json.RawMessage `json:"value"`
+}
+
+// encodeValue converts the human-readable value into the payload of a GroupWrite telegram using the given dpt.
+func encodeValue(dptName string, value string) ([]byte, error) {
+	v, found := dpt.Produce(dptName)
+	if !found {
+		return nil, fmt.Errorf("can not find dpt description for \"%s\"", dptName)
+	}
+	datapoint := v.(DPT)
+	typedValue := reflect.ValueOf(datapoint).Elem()
+	value = strings.TrimSpace(value)
+
+	kind := typedValue.Kind()
+	switch {
+	case kind == reflect.Bool:
+		switch strings.ToLower(value) {
+		case "on":
+			typedValue.SetBool(true)
+		case "off":
+			typedValue.SetBool(false)
+		default:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("can not parse \"%s\" as boolean: %s", value, err)
+			}
+			typedValue.SetBool(b)
+		}
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, typedValue.Type().Bits())
+		if err != nil {
+			return nil, fmt.Errorf("can not parse \"%s\" as integer: %s", value, err)
+		}
+		typedValue.SetInt(i)
+	case kind >= reflect.Uint && kind <= reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, typedValue.Type().Bits())
+		if err != nil {
+			return nil, fmt.Errorf("can not parse \"%s\" as unsigned integer: %s", value, err)
+		}
+		typedValue.SetUint(u)
+	case kind >= reflect.Float32 && kind <= reflect.Float64:
+		f, err := strconv.ParseFloat(value, typedValue.Type().Bits())
+		if err != nil {
+			return nil, fmt.Errorf("can not parse \"%s\" as number: %s", value, err)
+		}
+		typedValue.SetFloat(f)
+	case kind == reflect.String:
+		typedValue.SetString(value)
+	default:
+		return nil, fmt.Errorf("can not write values of dpt %s", dptName)
+	}
+	return datapoint.Pack(), nil
+}
+
+// NewWriteHandler creates a handler which sends the values of WriteRequests as GroupWrite telegrams through the
+// connection of the matching exporter. Only group addresses which are marked as Writable are accepted. Every request
+// is logged for audit.
+func NewWriteHandler(exporters []MetricsExporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request WriteRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "can not parse write request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		value := string(request.Value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		logger := slog.With(
+			"user", requestUser(r),
+			"remoteAddress", r.RemoteAddr,
+			"address", request.Address.String(),
+			"value", value,
+		)
+		if request.Installation != "" {
+			logger = logger.With("installation", request.Installation)
+		}
+
+		exporter := findExporter(exporters, request.Installation)
+		if exporter == nil {
+			logger.Warn("Reject write request for unknown installation")
+			http.Error(w, fmt.Sprintf("unknown installation \"%s\"", request.Installation), http.StatusNotFound)
+			return
+		}
+		addressConfig, ok := exporter.Config().AddressConfigs[request.Address]
+		if !ok || !addressConfig.Writable {
+			logger.Warn("Reject write request for group address which is not writable")
+			http.Error(w, fmt.Sprintf("group address %s is not writable", request.Address), http.StatusForbidden)
+			return
+		}
+		if _, err := encodeValue(addressConfig.DPT, value); err != nil {
+			logger.Warn("Reject write request with invalid value: " + err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := exporter.Write(request.Address, value); err != nil {
+			logger.Warn("Can not write group address value: " + err.Error())
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		logger.Info("Wrote group address value", "dpt", addressConfig.DPT)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// findExporter returns the exporter of the installation. The installation can be empty if there is only one exporter.
+func findExporter(exporters []MetricsExporter, installation string) MetricsExporter {
+	if installation == "" && len(exporters) == 1 {
+		return exporters[0]
+	}
+	for _, exporter := range exporters {
+		if exporter.Installation() == installation {
+			return exporter
+		}
+	}
+	return nil
+}
+
+// requestUser returns the name of the authenticated user. It is either the basic auth username or the common name of
+// the client certificate.
+func requestUser(r *http.Request) string {
+	if username, _, ok := r.BasicAuth(); ok {
+		return username
+	}
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	return ""
+}
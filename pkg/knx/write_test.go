@@ -0,0 +1,115 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knx
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/vapourismo/knx-go/knx"
+	"github.com/vapourismo/knx-go/knx/cemi"
+)
+
+func Test_encodeValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		dpt     string
+		value   string
+		want    []byte
+		wantErr bool
+	}{
+		{"switch on", "1.001", "on", []byte{1}, false},
+		{"switch false", "1.001", "false", []byte{0}, false},
+		{"invalid boolean", "1.001", "maybe", nil, true},
+		{"percentage", "5.001", "100", []byte{0, 255}, false},
+		{"temperature", "9.001", " 21.5 ", []byte{0, 0x0c, 0x33}, false},
+		{"invalid number", "9.001", "warm", nil, true},
+		{"counter", "12.001", "42", []byte{0, 0, 0, 0, 42}, false},
+		{"negative counter", "12.001", "-1", nil, true},
+		{"signed counter", "13.001", "-1", []byte{0, 0xff, 0xff, 0xff, 0xff}, false},
+		{"unsupported dpt", "10.001", "12:00:00", nil, true},
+		{"unknown dpt", "0.000", "1", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeValue(tt.dpt, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("encodeValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewWriteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		sendErr    error
+		wantSend   []byte
+		wantStatus int
+	}{
+		{"write number", `{"address":"0/0/1","value":21.5}`, nil, []byte{0, 0x0c, 0x33}, http.StatusNoContent},
+		{"write string", `{"installation":"home","address":"0/0/2","value":"on"}`, nil, []byte{1}, http.StatusNoContent},
+		{"invalid body", `{"address":`, nil, nil, http.StatusBadRequest},
+		{"unknown installation", `{"installation":"office","address":"0/0/1","value":1}`, nil, nil, http.StatusNotFound},
+		{"not writable", `{"address":"0/0/3","value":1}`, nil, nil, http.StatusForbidden},
+		{"not configured", `{"address":"0/0/4","value":1}`, nil, nil, http.StatusForbidden},
+		{"invalid value", `{"address":"0/0/1","value":"warm"}`, nil, nil, http.StatusBadRequest},
+		{"send error", `{"address":"0/0/2","value":true}`, fmt.Errorf("closed"), []byte{1}, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := NewMockGroupClient(ctrl)
+			if tt.wantSend != nil {
+				client.EXPECT().Send(gomock.Any()).DoAndReturn(func(event knx.GroupEvent) error {
+					assert.Equal(t, knx.GroupWrite, event.Command)
+					assert.Equal(t, cemi.IndividualAddr(0x1101), event.Source)
+					assert.Equal(t, tt.wantSend, event.Data)
+					return tt.sendErr
+				})
+			}
+			exporter := &metricsExporter{
+				config: &Config{
+					Installation: "home",
+					Connection:   Connection{PhysicalAddress: 0x1101},
+					AddressConfigs: GroupAddressConfigSet{
+						1: {Name: "setpoint", DPT: "9.001", Writable: true},
+						2: {Name: "light", DPT: "1.001", Writable: true},
+						3: {Name: "temperature", DPT: "9.001"},
+					},
+				},
+				client:         client,
+				messageCounter: prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"direction", "processed"}),
+			}
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/api/v1/write", strings.NewReader(tt.body))
+			request.SetBasicAuth("knx", "secret")
+			NewWriteHandler([]MetricsExporter{exporter}).ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code, recorder.Body.String())
+		})
+	}
+}
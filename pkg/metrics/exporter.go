@@ -17,20 +17,43 @@ package metrics
 //go:generate mockgen -destination=fake/exporterMocks.go -package=fake -source=exporter.go
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/coreos/go-systemd/v22/daemon"
+	"github.com/ghodss/yaml"
 	"github.com/heptiolabs/healthcheck"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/exporter-toolkit/web"
 )
 
+// unixSocketPrefix marks listen addresses which are unix domain sockets.
+const unixSocketPrefix = "unix://"
+
+// WebConfig defines the listen address and the security of the http server.
+type WebConfig struct {
+	// ListenAddress of the http server. i.e. 0.0.0.0:8080, [::]:8080 or unix:///run/knx-exporter.sock
+	ListenAddress string
+	// ConfigFile is a web configuration file of the Prometheus exporter-toolkit which enables TLS, client
+	// certificate authentication and basic authentication. The certificates are reloaded for every new connection.
+	ConfigFile string
+}
+
 type exporter struct {
 	Port          uint16
 	health        healthcheck.Handler
 	meterRegistry *prometheus.Registry
 	server        *http.Server
+	mux           *http.ServeMux
+	webConfig     WebConfig
+	remoteWriter  *remoteWriter
 }
 
 type Exporter interface {
@@ -40,6 +63,12 @@ type Exporter interface {
 	Unregister(collector prometheus.Collector) bool
 	AddLivenessCheck(name string, check healthcheck.Check)
 	AddReadinessCheck(name string, check healthcheck.Check)
+	// EnableRemoteWrite pushes all metrics additionally to the remote_write endpoint of the config.
+	EnableRemoteWrite(config RemoteWriteConfig) error
+	// Handle registers an additional handler at the http server. All handlers are protected like the metrics.
+	Handle(pattern string, handler http.Handler)
+	// ConfigureWeb overrides the listen address and enables TLS and authentication using the web config file.
+	ConfigureWeb(config WebConfig) error
 }
 
 func NewExporter(port uint16, withGoMetrics bool) Exporter {
@@ -51,26 +80,34 @@ func NewExporter(port uint16, withGoMetrics bool) Exporter {
 		Port:          port,
 		health:        healthcheck.NewHandler(),
 		meterRegistry: registry,
-		server:        &http.Server{Addr: fmt.Sprintf("0.0.0.0:%d", port)},
+		server:        &http.Server{},
+		mux:           http.NewServeMux(),
+		webConfig:     WebConfig{ListenAddress: fmt.Sprintf("0.0.0.0:%d", port)},
 	}
 }
 
 func (e exporter) Run(ctx context.Context) error {
-	server := http.NewServeMux()
-
-	server.HandleFunc("/live", e.health.LiveEndpoint)
-	server.HandleFunc("/ready", e.health.ReadyEndpoint)
+	e.mux.HandleFunc("/live", e.health.LiveEndpoint)
+	e.mux.HandleFunc("/ready", e.health.ReadyEndpoint)
 	handler := promhttp.HandlerFor(e.meterRegistry, promhttp.HandlerOpts{EnableOpenMetrics: true})
-	server.Handle("/metrics", handler)
+	e.mux.Handle("/metrics", handler)
 	_, _ = daemon.SdNotify(false, daemon.SdNotifyReady)
 
-	e.server.Handler = server
+	e.server.Handler = e.mux
+	// Derive the request contexts from ctx so that long-running streams are finished on shutdown.
+	e.server.BaseContext = func(net.Listener) context.Context { return ctx }
+	if e.remoteWriter != nil {
+		go e.remoteWriter.Run(ctx)
+	}
 
+	listener, err := listen(e.webConfig.ListenAddress)
+	if err != nil {
+		return err
+	}
 	srvErr := make(chan error, 1)
 	go func() {
-		srvErr <- e.server.ListenAndServe()
+		srvErr <- web.Serve(listener, e.server, &web.FlagConfig{WebConfigFile: &e.webConfig.ConfigFile}, slog.Default())
 	}()
-	var err error
 	// Wait for interruption.
 	select {
 	case err = <-srvErr:
@@ -81,7 +118,7 @@ func (e exporter) Run(ctx context.Context) error {
 		// Stop receiving signal notifications as soon as possible.
 	}
 
-	// When Shutdown is called, ListenAndServe immediately returns ErrServerClosed.
+	// When Shutdown is called, Serve immediately returns ErrServerClosed.
 	return e.server.Shutdown(context.Background())
 }
 
@@ -100,3 +137,86 @@ func (e exporter) AddLivenessCheck(name string, check healthcheck.Check) {
 func (e exporter) AddReadinessCheck(name string, check healthcheck.Check) {
 	e.health.AddReadinessCheck(name, check)
 }
+
+func (e exporter) Handle(pattern string, handler http.Handler) {
+	e.mux.Handle(pattern, handler)
+}
+
+func (e *exporter) EnableRemoteWrite(config RemoteWriteConfig) error {
+	writer, err := newRemoteWriter(config, e.meterRegistry)
+	if err != nil {
+		return err
+	}
+	e.remoteWriter = writer
+	return nil
+}
+
+func (e *exporter) ConfigureWeb(config WebConfig) error {
+	if config.ListenAddress == "" {
+		config.ListenAddress = e.webConfig.ListenAddress
+	}
+	if config.ConfigFile != "" {
+		if err := web.Validate(config.ConfigFile); err != nil {
+			return fmt.Errorf("invalid web config file %s: %s", config.ConfigFile, err)
+		}
+	}
+	e.webConfig = config
+	return nil
+}
+
+// webConfigAuthentication contains the parts of the web config file which enable authentication.
+type webConfigAuthentication struct {
+	TLSServerConfig struct {
+		Cert           string `json:"cert"`
+		CertFile       string `json:"cert_file"`
+		ClientAuthType string `json:"client_auth_type"`
+	} `json:"tls_server_config"`
+	BasicAuthUsers map[string]string `json:"basic_auth_users"`
+}
+
+// RequiresAuthentication returns true if the web config file enables TLS together with basic authentication or
+// required client certificates. Basic authentication without TLS would send the passwords in plain text.
+func (c WebConfig) RequiresAuthentication() (bool, error) {
+	if c.ConfigFile == "" {
+		return false, nil
+	}
+	content, err := os.ReadFile(c.ConfigFile)
+	if err != nil {
+		return false, fmt.Errorf("can not read web config file %s: %s", c.ConfigFile, err)
+	}
+	var config webConfigAuthentication
+	if err = yaml.Unmarshal(content, &config); err != nil {
+		return false, fmt.Errorf("can not parse web config file %s: %s", c.ConfigFile, err)
+	}
+	tls := config.TLSServerConfig
+	if tls.Cert == "" && tls.CertFile == "" {
+		return false, nil
+	}
+	return len(config.BasicAuthUsers) > 0 || tls.ClientAuthType == "RequireAndVerifyClientCert", nil
+}
+
+// listen creates a tcp listener for ipv4 and ipv6 addresses or a unix domain socket listener for addresses with the
+// unix:// prefix. A stale socket file from a previous run is removed before.
+func listen(address string) (net.Listener, error) {
+	if !strings.HasPrefix(address, unixSocketPrefix) {
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return nil, fmt.Errorf("can not listen on %s: %s", address, err)
+		}
+		return listener, nil
+	}
+
+	path := strings.TrimPrefix(address, unixSocketPrefix)
+	if info, err := os.Stat(path); err == nil && info.Mode().Type() == fs.ModeSocket {
+		if err = os.Remove(path); err != nil {
+			return nil, fmt.Errorf("can not remove stale unix socket %s: %s", path, err)
+		}
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("can not check unix socket %s: %s", path, err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("can not listen on %s: %s", address, err)
+	}
+	return listener, nil
+}
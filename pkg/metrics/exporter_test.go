@@ -0,0 +1,226 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func Test_listen(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "stale.sock")
+	stale, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	// Keep the socket file like a crashed process would do.
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	assert.NoError(t, stale.Close())
+
+	tests := []struct {
+		name    string
+		address string
+		network string
+		wantErr bool
+	}{
+		{"ipv4", "127.0.0.1:0", "tcp", false},
+		{"ipv6", "[::1]:0", "tcp", false},
+		{"unix socket", "unix://" + filepath.Join(t.TempDir(), "knx.sock"), "unix", false},
+		{"stale unix socket", "unix://" + socket, "unix", false},
+		{"invalid address", "127.0.0.1", "", true},
+		{"missing socket directory", "unix:///does/not/exist/knx.sock", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.name == "ipv6" {
+				if probe, err := net.Listen("tcp6", "[::1]:0"); err != nil {
+					t.Skip("ipv6 is not available")
+				} else {
+					_ = probe.Close()
+				}
+			}
+			listener, err := listen(tt.address)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("listen() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				assert.Equal(t, tt.network, listener.Addr().Network())
+				assert.NoError(t, listener.Close())
+			}
+		})
+	}
+}
+
+func TestExporter_ConfigureWeb(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "web.yaml")
+	assert.NoError(t, os.WriteFile(invalid, []byte("basic_auth_users:\n  knx: plain\n"), 0600))
+
+	e := NewExporter(8080, false)
+	assert.Error(t, e.ConfigureWeb(WebConfig{ConfigFile: invalid}))
+	assert.Error(t, e.ConfigureWeb(WebConfig{ConfigFile: "does-not-exist.yaml"}))
+	assert.NoError(t, e.ConfigureWeb(WebConfig{}))
+	assert.Equal(t, "0.0.0.0:8080", e.(*exporter).webConfig.ListenAddress)
+}
+
+func TestWebConfig_RequiresAuthentication(t *testing.T) {
+	tests := []struct {
+		name      string
+		webConfig string
+		want      bool
+		wantErr   bool
+	}{
+		{"without config file", "", false, false},
+		{"tls only", "tls_server_config:\n  cert_file: server.crt\n  key_file: server.key\n", false, false},
+		{"basic auth without tls", "basic_auth_users:\n  knx: $2y$10$hash\n", false, false},
+		{"basic auth", "tls_server_config:\n  cert_file: server.crt\n  key_file: server.key\nbasic_auth_users:\n  knx: $2y$10$hash\n", true, false},
+		{"inline certificate", "tls_server_config:\n  cert: PEM\n  key: PEM\nbasic_auth_users:\n  knx: $2y$10$hash\n", true, false},
+		{"client certificates", "tls_server_config:\n  cert_file: server.crt\n  key_file: server.key\n  client_auth_type: RequireAndVerifyClientCert\n", true, false},
+		{"invalid config file", "basic_auth_users: [", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := WebConfig{}
+			if tt.webConfig != "" {
+				config.ConfigFile = filepath.Join(t.TempDir(), "web.yaml")
+				assert.NoError(t, os.WriteFile(config.ConfigFile, []byte(tt.webConfig), 0600))
+			}
+			got, err := config.RequiresAuthentication()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RequiresAuthentication() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExporter_RunWithWebConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, rootCAs := createServerCertificate(t, dir)
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		path       string
+		webConfig  string
+		tls        bool
+		username   string
+		password   string
+		wantStatus int
+	}{
+		{"plain http", "/metrics", "", false, "", "", http.StatusOK},
+		{"basic auth without credentials", "/metrics", "basic_auth_users:\n  knx: " + string(hash) + "\n", false, "", "", http.StatusUnauthorized},
+		{"basic auth with wrong password", "/api/test", "basic_auth_users:\n  knx: " + string(hash) + "\n", false, "knx", "wrong", http.StatusUnauthorized},
+		{"basic auth", "/api/test", "basic_auth_users:\n  knx: " + string(hash) + "\n", false, "knx", "secret", http.StatusOK},
+		{"tls", "/metrics", "tls_server_config:\n  cert_file: " + certFile + "\n  key_file: " + keyFile + "\n", true, "", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			socket := filepath.Join(t.TempDir(), "knx.sock")
+			config := WebConfig{ListenAddress: "unix://" + socket}
+			if tt.webConfig != "" {
+				config.ConfigFile = filepath.Join(t.TempDir(), "web.yaml")
+				assert.NoError(t, os.WriteFile(config.ConfigFile, []byte(tt.webConfig), 0600))
+			}
+			e := NewExporter(0, false)
+			assert.NoError(t, e.ConfigureWeb(config))
+			e.Handle("/api/", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() { done <- e.Run(ctx) }()
+			defer func() {
+				cancel()
+				assert.NoError(t, <-done)
+			}()
+
+			scheme := "http"
+			transport := &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+				},
+			}
+			if tt.tls {
+				scheme = "https"
+				transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs, ServerName: "localhost", MinVersion: tls.VersionTLS12}
+			}
+			client := &http.Client{Transport: transport, Timeout: time.Second}
+			request, err := http.NewRequest(http.MethodGet, scheme+"://localhost"+tt.path, nil)
+			assert.NoError(t, err)
+			if tt.username != "" {
+				request.SetBasicAuth(tt.username, tt.password)
+			}
+
+			var response *http.Response
+			assert.Eventually(t, func() bool {
+				response, err = client.Do(request)
+				return err == nil
+			}, 5*time.Second, 10*time.Millisecond)
+			if response != nil {
+				_ = response.Body.Close()
+				assert.Equal(t, tt.wantStatus, response.StatusCode)
+			}
+		})
+	}
+}
+
+// createServerCertificate creates a self-signed certificate for localhost and returns the paths of the certificate and
+// the key file as well as a pool containing the certificate.
+func createServerCertificate(t *testing.T, dir string) (string, string, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(certificate)
+	return certFile, keyFile, pool
+}
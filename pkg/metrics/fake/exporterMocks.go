@@ -20,8 +20,10 @@ package fake
 
 import (
 	context "context"
+	http "net/http"
 	reflect "reflect"
 
+	metrics "github.com/chr-fritz/knx-exporter/pkg/metrics"
 	gomock "github.com/golang/mock/gomock"
 	healthcheck "github.com/heptiolabs/healthcheck"
 	prometheus "github.com/prometheus/client_golang/prometheus"
@@ -74,6 +76,46 @@ func (mr *MockExporterMockRecorder) AddReadinessCheck(name, check interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReadinessCheck", reflect.TypeOf((*MockExporter)(nil).AddReadinessCheck), name, check)
 }
 
+// ConfigureWeb mocks base method.
+func (m *MockExporter) ConfigureWeb(config metrics.WebConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigureWeb", config)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfigureWeb indicates an expected call of ConfigureWeb.
+func (mr *MockExporterMockRecorder) ConfigureWeb(config interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureWeb", reflect.TypeOf((*MockExporter)(nil).ConfigureWeb), config)
+}
+
+// EnableRemoteWrite mocks base method.
+func (m *MockExporter) EnableRemoteWrite(config metrics.RemoteWriteConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableRemoteWrite", config)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableRemoteWrite indicates an expected call of EnableRemoteWrite.
+func (mr *MockExporterMockRecorder) EnableRemoteWrite(config interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableRemoteWrite", reflect.TypeOf((*MockExporter)(nil).EnableRemoteWrite), config)
+}
+
+// Handle mocks base method.
+func (m *MockExporter) Handle(pattern string, handler http.Handler) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Handle", pattern, handler)
+}
+
+// Handle indicates an expected call of Handle.
+func (mr *MockExporterMockRecorder) Handle(pattern, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handle", reflect.TypeOf((*MockExporter)(nil).Handle), pattern, handler)
+}
+
 // MustRegister mocks base method.
 func (m *MockExporter) MustRegister(collectors ...prometheus.Collector) {
 	m.ctrl.T.Helper()
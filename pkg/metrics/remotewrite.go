@@ -0,0 +1,376 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const defaultRemoteWriteInterval = 30 * time.Second
+const defaultRemoteWriteTimeout = 30 * time.Second
+const defaultRemoteWriteMaxBuffered = 1000
+
+// segmentExtension is the file extension of the requests which are buffered within the BufferDir.
+const segmentExtension = ".seg"
+
+// RemoteWriteConfig defines the remote_write endpoint to which all metrics are pushed.
+type RemoteWriteConfig struct {
+	// URL of the remote_write endpoint.
+	URL string
+	// Interval between two pushes. Default is 30s.
+	Interval time.Duration
+	// Timeout of a single push. Default is 30s.
+	Timeout time.Duration
+	// Username and Password for basic authentication.
+	Username string
+	Password string
+	// BearerToken for authentication. It is ignored if a Username is set.
+	BearerToken string
+	// Labels are added to all pushed series. i.e. to identify the site.
+	Labels map[string]string
+	// BufferDir is the directory where the requests are stored while the endpoint is not reachable. If empty, they are
+	// buffered in memory.
+	BufferDir string
+	// MaxBuffered is the maximum number of buffered requests. If exceeded, the oldest requests are dropped. Default
+	// is 1000.
+	MaxBuffered int
+}
+
+// errUnrecoverable marks pushes which are rejected by the endpoint and must not be retried.
+type errUnrecoverable struct {
+	err error
+}
+
+func (e errUnrecoverable) Error() string {
+	return e.err.Error()
+}
+
+type remoteWriter struct {
+	config   RemoteWriteConfig
+	gatherer prometheus.Gatherer
+	client   *http.Client
+	// pending contains the buffered requests if no BufferDir is configured.
+	pending [][]byte
+	now     func() time.Time
+	logger  *slog.Logger
+}
+
+func newRemoteWriter(config RemoteWriteConfig, gatherer prometheus.Gatherer) (*remoteWriter, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("remote write url is required")
+	}
+	if config.Interval == 0 {
+		config.Interval = defaultRemoteWriteInterval
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultRemoteWriteTimeout
+	}
+	if config.MaxBuffered == 0 {
+		config.MaxBuffered = defaultRemoteWriteMaxBuffered
+	}
+	if config.BufferDir != "" {
+		if err := os.MkdirAll(config.BufferDir, 0750); err != nil {
+			return nil, fmt.Errorf("can not create remote write buffer directory: %s", err)
+		}
+	}
+	return &remoteWriter{
+		config:   config,
+		gatherer: gatherer,
+		client:   &http.Client{Timeout: config.Timeout},
+		now:      time.Now,
+		logger:   slog.With("url", config.URL),
+	}, nil
+}
+
+// Run pushes all metrics at the configured interval until the context is done.
+func (w *remoteWriter) Run(ctx context.Context) {
+	w.logger.Info("Pushing metrics to remote write endpoint", "interval", w.config.Interval)
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.push(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// push gathers all metrics and sends them after all buffered requests. If a request can not be sent, it and all
+// following requests are buffered.
+func (w *remoteWriter) push(ctx context.Context) {
+	families, err := w.gatherer.Gather()
+	if err != nil {
+		w.logger.Warn("Can not gather all metrics for remote write: " + err.Error())
+	}
+	request := snappy.Encode(nil, encodeWriteRequest(families, w.config.Labels, w.now()))
+
+	for w.hasBuffered() {
+		buffered, drop := w.oldestBuffered()
+		if buffered != nil {
+			err = w.send(ctx, buffered)
+			if _, unrecoverable := err.(errUnrecoverable); err != nil && !unrecoverable {
+				w.logger.Warn("Remote write endpoint is still not reachable: " + err.Error())
+				w.buffer(request)
+				return
+			}
+			if err != nil {
+				w.logger.Warn("Drop buffered request as it was rejected: " + err.Error())
+			}
+		}
+		drop()
+	}
+
+	err = w.send(ctx, request)
+	if err == nil {
+		return
+	}
+	if _, unrecoverable := err.(errUnrecoverable); unrecoverable {
+		w.logger.Warn("Drop request as it was rejected: " + err.Error())
+		return
+	}
+	w.logger.Warn("Can not push metrics: " + err.Error())
+	w.buffer(request)
+}
+
+func (w *remoteWriter) send(ctx context.Context, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return errUnrecoverable{fmt.Errorf("can not create remote write request: %s", err)}
+	}
+	request.Header.Set("Content-Encoding", "snappy")
+	request.Header.Set("Content-Type", "application/x-protobuf")
+	request.Header.Set("User-Agent", "knx-exporter")
+	request.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.config.Username != "" {
+		request.SetBasicAuth(w.config.Username, w.config.Password)
+	} else if w.config.BearerToken != "" {
+		request.Header.Set("Authorization", "Bearer "+w.config.BearerToken)
+	}
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("can not push metrics: %s", err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	content, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	err = fmt.Errorf("remote write endpoint responded with %s: %s", response.Status, bytes.TrimSpace(content))
+	if response.StatusCode >= 400 && response.StatusCode < 500 && response.StatusCode != http.StatusTooManyRequests {
+		return errUnrecoverable{err}
+	}
+	return err
+}
+
+func (w *remoteWriter) hasBuffered() bool {
+	if w.config.BufferDir == "" {
+		return len(w.pending) > 0
+	}
+	return len(w.segments()) > 0
+}
+
+// oldestBuffered returns the oldest buffered request together with a function to drop it from the buffer.
+func (w *remoteWriter) oldestBuffered() ([]byte, func()) {
+	if w.config.BufferDir == "" {
+		return w.pending[0], func() { w.pending = w.pending[1:] }
+	}
+	segment := w.segments()[0]
+	drop := func() {
+		if err := os.Remove(segment); err != nil {
+			w.logger.Warn("Can not remove buffered request: " + err.Error())
+		}
+	}
+	content, err := os.ReadFile(segment)
+	if err != nil {
+		w.logger.Warn("Can not read buffered request: " + err.Error())
+		return nil, drop
+	}
+	return content, drop
+}
+
+// buffer stores the request and drops the oldest requests if there are too many.
+func (w *remoteWriter) buffer(request []byte) {
+	if w.config.BufferDir == "" {
+		w.pending = append(w.pending, request)
+		if len(w.pending) > w.config.MaxBuffered {
+			w.logger.Warn("Drop oldest buffered request as the buffer is full")
+			w.pending = w.pending[len(w.pending)-w.config.MaxBuffered:]
+		}
+		return
+	}
+
+	segment := filepath.Join(w.config.BufferDir, fmt.Sprintf("%020d%s", w.now().UnixNano(), segmentExtension))
+	if err := os.WriteFile(segment, request, 0640); err != nil {
+		w.logger.Warn("Can not buffer request: " + err.Error())
+		return
+	}
+	segments := w.segments()
+	for len(segments) > w.config.MaxBuffered {
+		w.logger.Warn("Drop oldest buffered request as the buffer is full", "file", segments[0])
+		if err := os.Remove(segments[0]); err != nil {
+			w.logger.Warn("Can not remove buffered request: " + err.Error())
+			return
+		}
+		segments = segments[1:]
+	}
+}
+
+// segments returns all buffered requests within the BufferDir ordered by their age.
+func (w *remoteWriter) segments() []string {
+	segments, err := filepath.Glob(filepath.Join(w.config.BufferDir, "*"+segmentExtension))
+	if err != nil {
+		w.logger.Warn("Can not list buffered requests: " + err.Error())
+		return nil
+	}
+	slices.Sort(segments)
+	return segments
+}
+
+// sample is a single value of a time series.
+type sample struct {
+	labels    map[string]string
+	value     float64
+	timestamp int64
+}
+
+// encodeWriteRequest encodes all metric families as protobuf WriteRequest of the remote write protocol. Metrics without
+// their own timestamp get the given timestamp.
+func encodeWriteRequest(families []*dto.MetricFamily, externalLabels map[string]string, now time.Time) []byte {
+	var request []byte
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			timestamp := now.UnixMilli()
+			if metric.TimestampMs != nil {
+				timestamp = metric.GetTimestampMs()
+			}
+			labels := make(map[string]string)
+			for name, value := range externalLabels {
+				labels[name] = value
+			}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			for _, s := range toSamples(family, metric, labels) {
+				s.timestamp = timestamp
+				request = protowire.AppendTag(request, 1, protowire.BytesType)
+				request = protowire.AppendBytes(request, encodeTimeSeries(s))
+			}
+		}
+	}
+	return request
+}
+
+// toSamples converts the metric into its samples. Histograms and summaries are split into their buckets, quantiles,
+// sum and count.
+func toSamples(family *dto.MetricFamily, metric *dto.Metric, labels map[string]string) []sample {
+	name := family.GetName()
+	newSample := func(suffix string, value float64, extraName string, extraValue string) sample {
+		sampleLabels := make(map[string]string, len(labels)+2)
+		for n, v := range labels {
+			sampleLabels[n] = v
+		}
+		sampleLabels["__name__"] = name + suffix
+		if extraName != "" {
+			sampleLabels[extraName] = extraValue
+		}
+		return sample{labels: sampleLabels, value: value}
+	}
+
+	switch family.GetType() {
+	case dto.MetricType_COUNTER:
+		return []sample{newSample("", metric.GetCounter().GetValue(), "", "")}
+	case dto.MetricType_GAUGE:
+		return []sample{newSample("", metric.GetGauge().GetValue(), "", "")}
+	case dto.MetricType_SUMMARY:
+		summary := metric.GetSummary()
+		var samples []sample
+		for _, q := range summary.GetQuantile() {
+			samples = append(samples, newSample("", q.GetValue(), "quantile", formatFloat(q.GetQuantile())))
+		}
+		return append(samples,
+			newSample("_sum", summary.GetSampleSum(), "", ""),
+			newSample("_count", float64(summary.GetSampleCount()), "", ""),
+		)
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		histogram := metric.GetHistogram()
+		var samples []sample
+		for _, b := range histogram.GetBucket() {
+			samples = append(samples, newSample("_bucket", float64(b.GetCumulativeCount()), "le", formatFloat(b.GetUpperBound())))
+		}
+		return append(samples,
+			newSample("_bucket", float64(histogram.GetSampleCount()), "le", "+Inf"),
+			newSample("_sum", histogram.GetSampleSum(), "", ""),
+			newSample("_count", float64(histogram.GetSampleCount()), "", ""),
+		)
+	default:
+		return []sample{newSample("", metric.GetUntyped().GetValue(), "", "")}
+	}
+}
+
+// encodeTimeSeries encodes the sample as protobuf TimeSeries with the labels sorted by their names.
+func encodeTimeSeries(s sample) []byte {
+	var names []string
+	for name := range s.labels {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var series []byte
+	for _, name := range names {
+		var label []byte
+		label = protowire.AppendTag(label, 1, protowire.BytesType)
+		label = protowire.AppendString(label, name)
+		label = protowire.AppendTag(label, 2, protowire.BytesType)
+		label = protowire.AppendString(label, s.labels[name])
+		series = protowire.AppendTag(series, 1, protowire.BytesType)
+		series = protowire.AppendBytes(series, label)
+	}
+
+	var value []byte
+	value = protowire.AppendTag(value, 1, protowire.Fixed64Type)
+	value = protowire.AppendFixed64(value, math.Float64bits(s.value))
+	value = protowire.AppendTag(value, 2, protowire.VarintType)
+	value = protowire.AppendVarint(value, uint64(s.timestamp))
+	series = protowire.AppendTag(series, 2, protowire.BytesType)
+	return protowire.AppendBytes(series, value)
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,210 @@
+// Copyright © 2026 Christian Fritz <mail@chr-fritz.de>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestEncodeWriteRequest(t *testing.T) {
+	now := time.UnixMilli(2000)
+	registry := prometheus.NewPedanticRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "knx_messages", Help: "messages"})
+	counter.Add(3)
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "duration", Help: "duration", Buckets: []float64{1}})
+	histogram.Observe(0.5)
+	registry.MustRegister(counter, histogram)
+
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	gauge := prometheus.NewMetricWithTimestamp(time.UnixMilli(1000), prometheus.MustNewConstMetric(
+		prometheus.NewDesc("knx_temperature", "temperature", []string{"room"}, nil), prometheus.GaugeValue, 21.5, "kitchen"))
+	gaugeRegistry := prometheus.NewPedanticRegistry()
+	gaugeRegistry.MustRegister(constCollector{gauge})
+	gaugeFamilies, err := gaugeRegistry.Gather()
+	assert.NoError(t, err)
+
+	got := decodeWriteRequest(t, encodeWriteRequest(append(families, gaugeFamilies...), map[string]string{"site": "home"}, now))
+	assert.Equal(t, []decodedSeries{
+		{map[string]string{"__name__": "duration_bucket", "le": "1", "site": "home"}, 1, 2000},
+		{map[string]string{"__name__": "duration_bucket", "le": "+Inf", "site": "home"}, 1, 2000},
+		{map[string]string{"__name__": "duration_sum", "site": "home"}, 0.5, 2000},
+		{map[string]string{"__name__": "duration_count", "site": "home"}, 1, 2000},
+		{map[string]string{"__name__": "knx_messages", "site": "home"}, 3, 2000},
+		{map[string]string{"__name__": "knx_temperature", "room": "kitchen", "site": "home"}, 21.5, 1000},
+	}, got)
+}
+
+func TestRemoteWriter_push(t *testing.T) {
+	tests := []struct {
+		name      string
+		bufferDir bool
+	}{
+		{"memory", false},
+		{"disk", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lock sync.Mutex
+			status := http.StatusServiceUnavailable
+			var received []decodedSeries
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				lock.Lock()
+				defer lock.Unlock()
+				assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+				assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				if status == http.StatusNoContent {
+					body, _ := io.ReadAll(r.Body)
+					decoded, err := snappy.Decode(nil, body)
+					assert.NoError(t, err)
+					received = append(received, decodeWriteRequest(t, decoded)...)
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "value", Help: "value"})
+			registry := prometheus.NewPedanticRegistry()
+			registry.MustRegister(gauge)
+
+			config := RemoteWriteConfig{URL: server.URL, BearerToken: "secret", MaxBuffered: 2}
+			if tt.bufferDir {
+				config.BufferDir = filepath.Join(t.TempDir(), "wal")
+			}
+			writer, err := newRemoteWriter(config, registry)
+			assert.NoError(t, err)
+
+			// Only the two newest pushes are buffered while the endpoint is not reachable.
+			for i := 1; i <= 3; i++ {
+				gauge.Set(float64(i))
+				writer.now = func() time.Time { return time.UnixMilli(int64(i)) }
+				writer.push(context.Background())
+			}
+			if tt.bufferDir {
+				segments, err := os.ReadDir(config.BufferDir)
+				assert.NoError(t, err)
+				assert.Len(t, segments, 2)
+			}
+
+			lock.Lock()
+			status = http.StatusNoContent
+			lock.Unlock()
+			gauge.Set(4)
+			writer.now = func() time.Time { return time.UnixMilli(4) }
+			writer.push(context.Background())
+
+			assert.Equal(t, []decodedSeries{
+				{map[string]string{"__name__": "value"}, 2, 2},
+				{map[string]string{"__name__": "value"}, 3, 3},
+				{map[string]string{"__name__": "value"}, 4, 4},
+			}, received)
+			assert.False(t, writer.hasBuffered())
+		})
+	}
+}
+
+func TestRemoteWriter_pushRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	writer, err := newRemoteWriter(RemoteWriteConfig{URL: server.URL}, prometheus.NewPedanticRegistry())
+	assert.NoError(t, err)
+	writer.push(context.Background())
+	assert.False(t, writer.hasBuffered())
+}
+
+type constCollector struct {
+	metric prometheus.Metric
+}
+
+func (c constCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.metric.Desc()
+}
+
+func (c constCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- c.metric
+}
+
+type decodedSeries struct {
+	labels    map[string]string
+	value     float64
+	timestamp int64
+}
+
+// decodeWriteRequest decodes the protobuf WriteRequest into a list of series with a single sample.
+func decodeWriteRequest(t *testing.T, request []byte) []decodedSeries {
+	var result []decodedSeries
+	forEachField(t, request, func(_ protowire.Number, series []byte) {
+		decoded := decodedSeries{labels: map[string]string{}}
+		forEachField(t, series, func(number protowire.Number, content []byte) {
+			if number == 1 {
+				var name, value string
+				forEachField(t, content, func(number protowire.Number, content []byte) {
+					if number == 1 {
+						name = string(content)
+					} else {
+						value = string(content)
+					}
+				})
+				decoded.labels[name] = value
+				return
+			}
+			for len(content) > 0 {
+				number, typ, n := protowire.ConsumeTag(content)
+				content = content[n:]
+				if number == 1 && typ == protowire.Fixed64Type {
+					v, n := protowire.ConsumeFixed64(content)
+					decoded.value = math.Float64frombits(v)
+					content = content[n:]
+				} else {
+					v, n := protowire.ConsumeVarint(content)
+					decoded.timestamp = int64(v)
+					content = content[n:]
+				}
+			}
+		})
+		result = append(result, decoded)
+	})
+	return result
+}
+
+func forEachField(t *testing.T, message []byte, f func(protowire.Number, []byte)) {
+	for len(message) > 0 {
+		number, typ, n := protowire.ConsumeTag(message)
+		assert.Equal(t, protowire.BytesType, typ)
+		message = message[n:]
+		content, n := protowire.ConsumeBytes(message)
+		assert.GreaterOrEqual(t, n, 0)
+		message = message[n:]
+		f(number, content)
+	}
+}